@@ -1,34 +1,159 @@
 package main
 
 import (
-	"database/sql"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	// timezone names have to work where the system has no zone database
+	_ "time/tzdata"
 
+	"grysha11/BlogAggregator/internal/cli"
 	"grysha11/BlogAggregator/internal/config"
 	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/logging"
+	"grysha11/BlogAggregator/internal/memory"
+	"grysha11/BlogAggregator/internal/migrations"
 	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/storage"
 	"grysha11/BlogAggregator/internal/ui"
 
 	tea "github.com/charmbracelet/bubbletea"
-	_ "github.com/lib/pq"
 )
 
+// exit codes of gator
+const (
+	exitOK		= 0
+	exitError	= 1
+	// the command line itself was wrong
+	exitUsage	= 2
+)
+
+// longRunning are the commands whose progress is logged at info level by default
+var longRunning = map[string]bool{
+	"agg": true,
+	"daemon": true,
+}
+
 func main() {
+	os.Exit(run(os.Args[1:]))
+}
+
+// run starts the TUI when there are no args, otherwise it runs the command they name
+func run(args []string) int {
+	// nothing is written anywhere, neither to a database nor to the config file
+	ephemeral := false
+	for len(args) > 0 && args[0] == "--ephemeral" {
+		ephemeral = true
+		args = args[1:]
+	}
+
+	var cmd cli.Command
+	if len(args) > 0 {
+		cmd = cli.Command{Name: args[0], Args: args[1:]}
+	}
+	if cmd.Name == "--help" || cmd.Name == "-h" {
+		cmd.Name = "help"
+	}
+
+	cmds := cli.NewCommands()
+
+	// help and typos are answered without a config or a database
+	if info, ok := cmds.Lookup(cmd.Name); (ok && info.Name == "help") || (!ok && cmd.Name != "") {
+		return exitCode(cmds.Run(&service.State{}, cmd))
+	}
+
 	cfg, err := config.Read()
+	if err != nil && !ephemeral {
+		fmt.Fprintf(os.Stderr, "Error while reading config: %v\n", err)
+		return exitError
+	}
+	cfg.Ephemeral = ephemeral
 
-	db, err := sql.Open("postgres", cfg.DBUrl)
+	// the TUI owns the terminal, a log line written to it would garble the screen
+	var logOut io.Writer = os.Stderr
+	if cmd.Name == "" {
+		logOut = io.Discard
+	}
+	// one-off commands print their result to the terminal, so without a log file
+	// or level configured only agg and daemon report their progress there
+	logLevel := cfg.LogLevel
+	if logLevel == "" && cfg.LogFile == "" && cmd.Name != "" && !longRunning[cmd.Name] {
+		logLevel = "warn"
+	}
+
+	logger, closeLog, err := logging.New(logLevel, cfg.LogFormat, cfg.LogFile, logOut)
 	if err != nil {
-		fmt.Printf("Error while connecting to database: %v\n", err)
-		os.Exit(1)
+		fmt.Fprintf(os.Stderr, "Error while setting up logging: %v\n", err)
+		return exitError
 	}
+	defer closeLog()
+
+	fetcher, err := service.NewFetcher(&cfg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error while setting up fetcher: %v\n", err)
+		return exitError
+	}
+
+	var s *service.State
+	if ephemeral {
+		s = service.New(memory.New(), &cfg)
+		if err := service.SeedDemo(s); err != nil {
+			fmt.Fprintf(os.Stderr, "Error while setting up demo: %v\n", err)
+			return exitError
+		}
+	} else {
+		db, err := storage.Open(cfg.DBUrl)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error while connecting to database: %v\n", err)
+			return exitError
+		}
+		defer db.Conn.Close()
 
-	s := service.New(database.New(db), &cfg)
+		// migrate is how an outdated schema gets fixed, so it can't be held back by one
+		if cmd.Name != "migrate" {
+			if err := migrations.Check(context.Background(), db.Conn, db.Backend); err != nil {
+				fmt.Fprintf(os.Stderr, "Error while checking database schema: %v\n", err)
+				return exitError
+			}
+		}
 
-	p := tea.NewProgram(ui.InitialModel(s))
+		s = service.New(db.Queries, &cfg)
+		s.Conn = db.Conn
+		s.Backend = db.Backend
+		s.Transact = func(fn func(service.Store) error) error {
+			return db.InTx(context.Background(), func(q *database.Queries) error {
+				return fn(q)
+			})
+		}
+	}
+	s.Logger = logger
+	s.Fetcher = fetcher
+	// hooks of posts fetched by this run are still delivered before it ends
+	defer s.Hooks.Close()
+
+	if cmd.Name == "" {
+		p := tea.NewProgram(ui.InitialModel(s))
+		if _, err := p.Run(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error occured: %v\n", err)
+			return exitError
+		}
+		return exitOK
+	}
+
+	return exitCode(cmds.Run(s, cmd))
+}
+
+// exitCode reports the error of a command and picks the exit code for it
+func exitCode(err error) int {
+	if err == nil {
+		return exitOK
+	}
 
-	if _, err := p.Run(); err != nil {
-		fmt.Printf("Error occured: %v\n", err)
-		os.Exit(1)
+	fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+	if errors.Is(err, cli.ErrUnknownCommand) || errors.Is(err, cli.ErrUsage) {
+		return exitUsage
 	}
-}
\ No newline at end of file
+	return exitError
+}
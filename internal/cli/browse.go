@@ -0,0 +1,236 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+const (
+	sortPublished	= "published"
+	sortFetched		= "fetched"
+)
+
+var browseFlags = []Flag{
+	{Name: "limit", Kind: FlagInt, Value: "n", Default: "2", Summary: "how many posts to show"},
+	{Name: "unread", Kind: FlagBool, Summary: "only show unread posts"},
+	{Name: "folder", Kind: FlagString, Value: "name", Summary: "only show feeds of a folder"},
+	{Name: "feed", Kind: FlagString, Value: "feed_url", Summary: "only show one feed"},
+	{Name: "since", Kind: FlagDate, Summary: "only show posts published on or after the day"},
+	{Name: "until", Kind: FlagDate, Summary: "only show posts published on or before the day"},
+	{Name: "title", Kind: FlagString, Value: "text", Summary: "only show posts with text in their title"},
+	{Name: "sort", Kind: FlagString, Default: sortPublished, Choices: []string{sortPublished, sortFetched}, Summary: "order posts by when they were published or fetched"},
+	{Name: "after", Kind: FlagString, Value: "cursor", Summary: "show the page after a cursor"},
+	{Name: "before", Kind: FlagString, Value: "cursor", Summary: "show the page before a cursor"},
+}
+
+// browseCursor points at the post a page starts or ends with,
+// it is handed to the user as an opaque string
+type browseCursor struct {
+	sort	string
+	time	time.Time
+	id		uuid.UUID
+}
+
+func (c browseCursor) String() string {
+	raw := fmt.Sprintf("%v|%v|%v", c.sort, c.time.UnixNano(), c.id)
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func parseBrowseCursor(str string) (browseCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(str)
+	if err != nil {
+		return browseCursor{}, fmt.Errorf("invalid cursor provided: %v", str)
+	}
+
+	parts := strings.Split(string(raw), "|")
+	if len(parts) != 3 {
+		return browseCursor{}, fmt.Errorf("invalid cursor provided: %v", str)
+	}
+	nanos, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return browseCursor{}, fmt.Errorf("invalid cursor provided: %v", str)
+	}
+	id, err := uuid.Parse(parts[2])
+	if err != nil {
+		return browseCursor{}, fmt.Errorf("invalid cursor provided: %v", str)
+	}
+
+	return browseCursor{sort: parts[0], time: time.Unix(0, nanos).UTC(), id: id}, nil
+}
+
+// escapeLike makes user text match literally inside an ILIKE pattern
+func escapeLike(str string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(str)
+}
+
+func HandlerBrowse(s *service.State, cmd Command, user database.User) error {
+	loc := service.Location(user)
+	params := database.GetPostsForUserParams{
+		UserID: user.ID,
+		Limit: int32(cmd.flagInt("limit")),
+		UnreadOnly: cmd.flagBool("unread"),
+	}
+	sort := cmd.flagString("sort")
+	after, before := cmd.flagString("after"), cmd.flagString("before")
+
+	// the limit used to be the only argument, so it still may be given without --limit
+	if len(cmd.Args) == 1 {
+		if cmd.flagGiven("limit") {
+			return cmd.usageError("limit given both as argument and as --limit")
+		}
+		limit, err := strconv.Atoi(cmd.Args[0])
+		if err != nil {
+			return fmt.Errorf("invalid limit provided: %v", cmd.Args[0])
+		}
+		params.Limit = int32(limit)
+	}
+	if params.Limit < 1 {
+		return fmt.Errorf("invalid limit provided: %v", params.Limit)
+	}
+
+	if name := cmd.flagString("folder"); name != "" {
+		folder, err := getFolder(s, user, name)
+		if err != nil {
+			return err
+		}
+		params.FolderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+	}
+	if feedURL := cmd.flagString("feed"); feedURL != "" {
+		feed, err := getFeed(s, feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	if since, ok := cmd.flagDate("since", loc); ok {
+		params.Since = sql.NullTime{Time: since, Valid: true}
+	}
+	if until, ok := cmd.flagDate("until", loc); ok {
+		// the whole until day is included
+		params.Until = sql.NullTime{Time: until.AddDate(0, 0, 1), Valid: true}
+	}
+	if title := cmd.flagString("title"); title != "" {
+		params.Title = sql.NullString{String: escapeLike(title), Valid: true}
+	}
+
+	if after != "" && before != "" {
+		return cmd.usageError("--after and --before can't be used together")
+	}
+	if after != "" || before != "" {
+		cursor, err := parseBrowseCursor(after + before)
+		if err != nil {
+			return err
+		}
+		if cursor.sort != sort {
+			return fmt.Errorf("cursor belongs to --sort %v, not %v", cursor.sort, sort)
+		}
+		params.CursorTime = sql.NullTime{Time: cursor.time, Valid: true}
+		params.CursorID = cursor.id
+		params.Reverse = before != ""
+	}
+	params.SortByFetched = sort == sortFetched
+
+	posts, hidden, err := service.VisiblePosts(s, user, params)
+	if err != nil {
+		return err
+	}
+
+	// pages before the cursor come oldest first
+	if params.Reverse {
+		for i, j := 0, len(posts)-1; i < j; i, j = i+1, j-1 {
+			posts[i], posts[j] = posts[j], posts[i]
+		}
+	}
+
+	if hidden > 0 {
+		fmt.Printf("Found %v posts for user %v (%v hidden by mutes):\n", len(posts), user.Name, hidden)
+	} else {
+		fmt.Printf("Found %v posts for user %v:\n", len(posts), user.Name)
+	}
+	for _, post := range posts {
+		var marks []string
+		if !post.IsRead {
+			marks = append(marks, "unread")
+		}
+		if post.RevisedAt.Valid {
+			marks = append(marks, fmt.Sprintf("updated %v", service.Ago(post.RevisedAt.Time, time.Now())))
+		}
+
+		if len(marks) > 0 {
+			fmt.Printf("--- %s --- (%v)\n", post.Title, strings.Join(marks, ", "))
+		} else {
+			fmt.Printf("--- %s ---\n", post.Title)
+		}
+		fmt.Printf("    %v\n", post.Description.String)
+		fmt.Printf("    Feed: %v\n", post.FeedName)
+		fmt.Printf("    Published: %v\n", service.FormatTime(post.PublishedAt, loc))
+		alsoIn, err := service.AlsoIn(s, user, post)
+		if err != nil {
+			return err
+		}
+		if len(alsoIn) > 0 {
+			fmt.Printf("    Also in: %v\n", strings.Join(alsoIn, ", "))
+		}
+		fmt.Printf("    Link: %s\n", post.Url)
+		if post.RevisedAt.Valid {
+			if err := printPostChanges(s, post); err != nil {
+				return err
+			}
+		}
+		fmt.Println("=====================================")
+	}
+
+	if len(posts) == 0 {
+		return nil
+	}
+
+	cursorOf := func(post database.GetPostsForUserRow) browseCursor {
+		if sort == sortFetched {
+			return browseCursor{sort: sort, time: post.CreatedAt, id: post.ID}
+		}
+		return browseCursor{sort: sort, time: post.PublishedAt, id: post.ID}
+	}
+
+	// a short page in a direction means there is nothing more that way
+	if after != "" || (before != "" && len(posts) == int(params.Limit)) {
+		fmt.Printf("Previous page: --before %v\n", cursorOf(posts[0]))
+	}
+	if before != "" || len(posts) == int(params.Limit) {
+		fmt.Printf("Next page: --after %v\n", cursorOf(posts[len(posts)-1]))
+	}
+
+	return nil
+}
+
+func printPostChanges(s *service.State, post database.GetPostsForUserRow) error {
+	revision, err := s.DB.GetLatestPostRevision(context.Background(), post.ID)
+	if err == sql.ErrNoRows {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("    Changes:\n")
+	if revision.Title != post.Title {
+		fmt.Printf("      Title: %v\n", service.DiffWords(revision.Title, post.Title))
+	}
+	if revision.Description.String != post.Description.String {
+		fmt.Printf("      Description: %v\n", service.DiffWords(revision.Description.String, post.Description.String))
+	}
+	if revision.Content.String != post.Content.String {
+		fmt.Printf("      Content was edited\n")
+	}
+
+	return nil
+}
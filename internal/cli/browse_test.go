@@ -0,0 +1,219 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/servicetest"
+
+	"github.com/google/uuid"
+)
+
+var (
+	titleLine		= regexp.MustCompile(`(?m)^--- (.*?) ---`)
+	nextLine		= regexp.MustCompile(`(?m)^Next page: --after (\S+)$`)
+	previousLine	= regexp.MustCompile(`(?m)^Previous page: --before (\S+)$`)
+)
+
+// browsePage runs browse and splits what it printed into the post titles and the page cursors
+func browsePage(t *testing.T, s *service.State, args ...string) (titles []string, next, previous string) {
+	t.Helper()
+
+	out := mustRun(t, s, append([]string{"browse"}, args...)...)
+	for _, match := range titleLine.FindAllStringSubmatch(out, -1) {
+		titles = append(titles, match[1])
+	}
+	if match := nextLine.FindStringSubmatch(out); match != nil {
+		next = match[1]
+	}
+	if match := previousLine.FindStringSubmatch(out); match != nil {
+		previous = match[1]
+	}
+	return titles, next, previous
+}
+
+// seedPosts adds a followed feed with n posts, published and fetched an hour apart in the same order
+func seedPosts(t *testing.T, s *service.State, n int) {
+	t.Helper()
+
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "addfeed", "blog", "http://example.com/feed")
+	feed, err := s.DB.GetFeedByURL(context.Background(), "http://example.com/feed")
+	if err != nil {
+		t.Fatalf("GetFeedByURL: %v", err)
+	}
+
+	start := time.Now().UTC().Add(-time.Duration(n) * time.Hour)
+	for i := range n {
+		at := start.Add(time.Duration(i) * time.Hour)
+		_, err := s.DB.CreatePost(context.Background(), database.CreatePostParams{
+			ID: uuid.New(),
+			CreatedAt: at,
+			UpdatedAt: at,
+			Title: fmt.Sprintf("Post %v", i),
+			Url: fmt.Sprintf("http://example.com/%v", i),
+			PublishedAt: at,
+			FeedID: feed.ID,
+		})
+		if err != nil {
+			t.Fatalf("CreatePost: %v", err)
+		}
+	}
+}
+
+func TestBrowsePages(t *testing.T) {
+	for _, sort := range []string{sortPublished, sortFetched} {
+		t.Run(sort, func(t *testing.T) {
+			s := servicetest.NewState(t)
+			seedPosts(t, s, 5)
+
+			first, next, previous := browsePage(t, s, "--limit", "2", "--sort", sort)
+			if !slices.Equal(first, []string{"Post 4", "Post 3"}) || next == "" || previous != "" {
+				t.Fatalf("first page = %v next %q previous %q", first, next, previous)
+			}
+
+			second, next, previous := browsePage(t, s, "--limit", "2", "--sort", sort, "--after", next)
+			if !slices.Equal(second, []string{"Post 2", "Post 1"}) || next == "" || previous == "" {
+				t.Fatalf("second page = %v next %q previous %q", second, next, previous)
+			}
+
+			last, lastNext, _ := browsePage(t, s, "--limit", "2", "--sort", sort, "--after", next)
+			if !slices.Equal(last, []string{"Post 0"}) || lastNext != "" {
+				t.Fatalf("last page = %v next %q", last, lastNext)
+			}
+
+			// going back from the second page gives the first one again, newest first
+			back, _, _ := browsePage(t, s, "--limit", "2", "--sort", sort, "--before", previous)
+			if !slices.Equal(back, first) {
+				t.Fatalf("page before the second one = %v, want %v", back, first)
+			}
+		})
+	}
+}
+
+func TestBrowseRejectsCursorOfAnotherSort(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedPosts(t, s, 3)
+
+	_, next, _ := browsePage(t, s, "--limit", "1", "--sort", sortPublished)
+	if _, err := run(t, s, "browse", "--sort", sortFetched, "--after", next); err == nil {
+		t.Fatalf("a published cursor was accepted for --sort fetched")
+	}
+}
+
+func TestBrowseHidesMutedPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedPosts(t, s, 4)
+	mustRun(t, s, "mute", "keyword", "Post 2")
+
+	titles, _, _ := browsePage(t, s, "--limit", "3")
+	if !slices.Equal(titles, []string{"Post 3", "Post 1", "Post 0"}) {
+		t.Fatalf("browse with a mute = %v", titles)
+	}
+}
+
+// seedSighting is seedPosts with a second feed, followed only by bob, in which alice's Post 1 was sighted too
+func seedSighting(t *testing.T, s *service.State) {
+	t.Helper()
+
+	seedPosts(t, s, 2)
+	mustRun(t, s, "addfeed", "mirror", "http://example.com/mirror")
+	mustRun(t, s, "register", "bob")
+	mustRun(t, s, "follow", "http://example.com/mirror")
+
+	mirror, err := s.DB.GetFeedByURL(context.Background(), "http://example.com/mirror")
+	if err != nil {
+		t.Fatalf("GetFeedByURL: %v", err)
+	}
+	post, err := s.DB.GetPostByURL(context.Background(), "http://example.com/1")
+	if err != nil {
+		t.Fatalf("GetPostByURL: %v", err)
+	}
+	err = s.DB.AddPostSighting(context.Background(), database.AddPostSightingParams{PostID: post.ID, FeedID: mirror.ID, SeenAt: time.Now().UTC()})
+	if err != nil {
+		t.Fatalf("AddPostSighting: %v", err)
+	}
+}
+
+func TestBrowseShowsPostsSightedInFollowedFeeds(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedSighting(t, s)
+
+	out := mustRun(t, s, "browse", "--limit", "5")
+	titles := titleLine.FindAllStringSubmatch(out, -1)
+	if len(titles) != 1 || titles[0][1] != "Post 1" {
+		t.Fatalf("bob browses %v, want the sighted post only:\n%v", titles, out)
+	}
+	if !strings.Contains(out, "Feed: mirror\n") || !strings.Contains(out, "Also in: blog\n") {
+		t.Errorf("sighted post isn't shown through the mirror feed:\n%v", out)
+	}
+
+	// alice follows both feeds and sees the post once, through its own feed
+	mustRun(t, s, "login", "alice")
+	aliceTitles, _, _ := browsePage(t, s, "--limit", "5")
+	if !slices.Equal(aliceTitles, []string{"Post 1", "Post 0"}) {
+		t.Errorf("alice browses %v, want each post once", aliceTitles)
+	}
+}
+
+func TestMarkAllReadCoversSightedPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedSighting(t, s)
+
+	if out := mustRun(t, s, "following"); !strings.Contains(out, "mirror (1 unread)") {
+		t.Fatalf("sighted post isn't counted as unread:\n%v", out)
+	}
+
+	mustRun(t, s, "markallread")
+	if out := mustRun(t, s, "following"); !strings.Contains(out, "mirror (0 unread)") {
+		t.Errorf("following after markallread:\n%v", out)
+	}
+	if titles, _, _ := browsePage(t, s, "--unread"); len(titles) != 0 {
+		t.Errorf("browse --unread after markallread = %v", titles)
+	}
+}
+
+func TestBrowseCollapsesClustersAmongFilteredPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedPosts(t, s, 3)
+
+	var posts []database.Post
+	for i := range 3 {
+		post, err := s.DB.GetPostByURL(context.Background(), fmt.Sprintf("http://example.com/%v", i))
+		if err != nil {
+			t.Fatalf("GetPostByURL: %v", err)
+		}
+		posts = append(posts, post)
+	}
+	cluster := uuid.NullUUID{UUID: posts[0].ID, Valid: true}
+	for _, post := range []database.Post{posts[0], posts[2]} {
+		if err := s.DB.SetPostCluster(context.Background(), database.SetPostClusterParams{ID: post.ID, ClusterID: cluster}); err != nil {
+			t.Fatalf("SetPostCluster: %v", err)
+		}
+	}
+
+	if titles, _, _ := browsePage(t, s, "--limit", "5"); !slices.Equal(titles, []string{"Post 1", "Post 0"}) {
+		t.Fatalf("browse = %v, want the story of Post 0 and 2 once", titles)
+	}
+	if titles, _, _ := browsePage(t, s, "--limit", "5", "--title", "Post 2"); !slices.Equal(titles, []string{"Post 2"}) {
+		t.Errorf("browse --title = %v, want the later post of the story", titles)
+	}
+
+	alice, err := s.DB.GetUserByName(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	if err := s.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{UserID: alice.ID, PostID: posts[0].ID, ReadAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("MarkPostRead: %v", err)
+	}
+	if titles, _, _ := browsePage(t, s, "--limit", "5", "--unread"); !slices.Equal(titles, []string{"Post 2", "Post 1"}) {
+		t.Errorf("browse --unread = %v, want the story shown by its unread post", titles)
+	}
+}
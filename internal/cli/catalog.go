@@ -0,0 +1,84 @@
+package cli
+
+// groups of commands, in the order help shows them
+const (
+	groupAccounts		= "Accounts"
+	groupFeeds			= "Feeds"
+	groupFolders		= "Folders"
+	groupReading		= "Reading"
+	groupHooks			= "Hooks"
+	groupAggregation	= "Aggregation and maintenance"
+	groupHelp			= "Help"
+)
+
+// NewCommands returns the registry of every command gator has
+func NewCommands() *Commands {
+	c := &Commands{}
+
+	// accounts
+	c.Register(CommandInfo{Name: "login", Group: groupAccounts, Summary: "log in as an existing user", Usage: "<name>", MinArgs: 1, MaxArgs: 1, Handler: HandlerLogin})
+	c.Register(CommandInfo{Name: "register", Group: groupAccounts, Summary: "create a user and log in as them", Usage: "<name>", MinArgs: 1, MaxArgs: 1, Handler: HandlerRegister})
+	c.Register(CommandInfo{Name: "reset", Group: groupAccounts, Summary: "delete every user, feed and follow", Handler: HandlerReset})
+	c.Register(CommandInfo{Name: "users", Group: groupAccounts, Summary: "list users, marking the logged in one", Handler: HandlerUsers})
+	c.Register(CommandInfo{Name: "renameuser", Group: groupAccounts, Summary: "change the name of the logged in user", Usage: "<new_name>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerRenameUser})
+	c.Register(CommandInfo{Name: "deleteuser", Group: groupAccounts, Summary: "delete the logged in user, admins may delete anyone", Usage: "[name]", MaxArgs: 1, Flags: deleteUserFlags, UserHandler: HandlerDeleteUser})
+	c.Register(CommandInfo{Name: "setadmin", Group: groupAccounts, Summary: "give or take admin rights", Usage: "<user> <yes|no>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerSetAdmin})
+	c.Register(CommandInfo{Name: "timezone", Aliases: []string{"tz"}, Group: groupAccounts, Summary: "show or set the timezone dates are shown in", Usage: "[zone|local]", MaxArgs: 1, UserHandler: HandlerTimezone})
+	c.Register(CommandInfo{Name: "exportme", Group: groupAccounts, Summary: "export everything stored about the logged in user as JSON", Usage: "[file]", MaxArgs: 1, UserHandler: HandlerExportMe})
+
+	// feeds
+	c.Register(CommandInfo{Name: "addfeed", Group: groupFeeds, Summary: "add a feed and follow it", Usage: "<feed_name> <feed_url>", MinArgs: 2, MaxArgs: 2, Flags: followFlags, UserHandler: HandlerAddFeed})
+	c.Register(CommandInfo{Name: "feeds", Group: groupFeeds, Summary: "list every feed", Handler: HandlerFeeds})
+	c.Register(CommandInfo{Name: "feedstatus", Group: groupFeeds, Summary: "show when followed feeds were fetched and how that went", Usage: "[feed_url]", MaxArgs: 1, UserHandler: HandlerFeedStatus})
+	c.Register(CommandInfo{Name: "deletefeed", Aliases: []string{"delfeed"}, Group: groupFeeds, Summary: "delete a feed with its posts", Usage: "<feed_url>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerDeleteFeed})
+	c.Register(CommandInfo{Name: "renamefeed", Group: groupFeeds, Summary: "change the name of a feed", Usage: "<feed_url> <name>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerRenameFeed})
+	c.Register(CommandInfo{Name: "setfeedurl", Group: groupFeeds, Summary: "move a feed to a new url", Usage: "<feed_url> <new_url>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerSetFeedURL})
+	c.Register(CommandInfo{Name: "transferfeed", Group: groupFeeds, Summary: "hand a feed over to another user", Usage: "<feed_url> <user>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerTransferFeed})
+	c.Register(CommandInfo{Name: "follow", Group: groupFeeds, Summary: "follow a feed", Usage: "<feed_url>", MinArgs: 1, MaxArgs: 1, Flags: followFlags, UserHandler: HandlerFollow})
+	c.Register(CommandInfo{Name: "following", Aliases: []string{"follows"}, Group: groupFeeds, Summary: "list followed feeds", UserHandler: HandlerFollowing})
+	c.Register(CommandInfo{Name: "unfollow", Group: groupFeeds, Summary: "stop following a feed", Usage: "<feed_url>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerUnfollow})
+	c.Register(CommandInfo{Name: "renamefollow", Group: groupFeeds, Summary: "show a followed feed under your own name, - goes back to its name", Usage: "<feed_url> <name|->", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerRenameFollow})
+	c.Register(CommandInfo{Name: "notefollow", Group: groupFeeds, Summary: "keep notes on a followed feed, - removes them", Usage: "<feed_url> <notes|->", MinArgs: 2, MaxArgs: Many, UserHandler: HandlerNoteFollow})
+
+	// folders
+	c.Register(CommandInfo{Name: "mkfolder", Group: groupFolders, Summary: "create a folder", Usage: "<name>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerMkFolder})
+	c.Register(CommandInfo{Name: "renamefolder", Group: groupFolders, Summary: "rename a folder", Usage: "<name> <new_name>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerRenameFolder})
+	c.Register(CommandInfo{Name: "rmfolder", Group: groupFolders, Summary: "delete a folder, its feeds stay followed", Usage: "<name>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerRmFolder})
+	c.Register(CommandInfo{Name: "tagfeed", Group: groupFolders, Summary: "put a followed feed into a folder", Usage: "<feed_url> <folder>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerTagFeed})
+	c.Register(CommandInfo{Name: "untagfeed", Group: groupFolders, Summary: "take a feed out of a folder", Usage: "<feed_url> <folder>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerUntagFeed})
+	c.Register(CommandInfo{Name: "movefeed", Group: groupFolders, Summary: "move a feed out of its folders into another one", Usage: "<feed_url> <folder>", MinArgs: 2, MaxArgs: 2, UserHandler: HandlerMoveFeed})
+
+	// reading
+	c.Register(CommandInfo{Name: "browse", Group: groupReading, Summary: "show posts of followed feeds, newest first", Usage: "[limit]", MaxArgs: 1, Flags: browseFlags, UserHandler: HandlerBrowse})
+	c.Register(CommandInfo{Name: "search", Group: groupReading, Summary: "search posts by words in their title and text", Usage: "<query...>", Flags: searchFlags, MinArgs: 1, MaxArgs: Many, UserHandler: HandlerSearch})
+	c.Register(CommandInfo{Name: "read", Group: groupReading, Summary: "mark a post as read", Usage: "<post_url|post_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerRead})
+	c.Register(CommandInfo{Name: "unread", Group: groupReading, Summary: "mark a post as unread", Usage: "<post_url|post_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerUnread})
+	c.Register(CommandInfo{Name: "markallread", Aliases: []string{"readall"}, Group: groupReading, Summary: "mark every post, or every post of a feed, as read", Usage: "[feed_url]", MaxArgs: 1, UserHandler: HandlerMarkAllRead})
+	c.Register(CommandInfo{Name: "star", Group: groupReading, Summary: "star a post", Usage: "<post_url|post_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerStar})
+	c.Register(CommandInfo{Name: "unstar", Group: groupReading, Summary: "take the star off a post", Usage: "<post_url|post_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerUnstar})
+	c.Register(CommandInfo{Name: "starred", Aliases: []string{"stars"}, Group: groupReading, Summary: "list starred posts", UserHandler: HandlerStarred})
+	c.Register(CommandInfo{Name: "later", Aliases: []string{"readlater"}, Group: groupReading, Summary: "add a post to the read later queue, or take it out", Usage: "<post_url|post_id>", MinArgs: 1, MaxArgs: 1, Flags: laterFlags, UserHandler: HandlerLater})
+	c.Register(CommandInfo{Name: "queue", Group: groupReading, Summary: "list the read later queue", UserHandler: HandlerQueue})
+	c.Register(CommandInfo{Name: "exportsaved", Group: groupReading, Summary: "export starred and queued posts as JSON", Usage: "[file]", MaxArgs: 1, UserHandler: HandlerExportSaved})
+	c.Register(CommandInfo{Name: "mute", Group: groupReading, Summary: "hide posts matching a rule", Usage: "<keyword|regex|author|category> <pattern>", MinArgs: 2, MaxArgs: 2, Flags: muteFlags, UserHandler: HandlerMute})
+	c.Register(CommandInfo{Name: "unmute", Group: groupReading, Summary: "remove a mute rule", Usage: "<mute_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerUnmute})
+	c.Register(CommandInfo{Name: "mutes", Group: groupReading, Summary: "list mute rules with how many posts they hide", UserHandler: HandlerMutes})
+
+	// hooks
+	c.Register(CommandInfo{Name: "addhook", Group: groupHooks, Summary: "call a webhook or run a command for every new post", Usage: "<webhook|command> <url|command>", MinArgs: 2, MaxArgs: 2, Flags: addHookFlags, UserHandler: HandlerAddHook})
+	c.Register(CommandInfo{Name: "hooks", Group: groupHooks, Summary: "list hooks", UserHandler: HandlerHooks})
+	c.Register(CommandInfo{Name: "delhook", Aliases: []string{"deletehook"}, Group: groupHooks, Summary: "delete a hook", Usage: "<hook_id>", MinArgs: 1, MaxArgs: 1, UserHandler: HandlerDeleteHook})
+	c.Register(CommandInfo{Name: "hooklog", Group: groupHooks, Summary: "show the latest hook deliveries", Flags: hookLogFlags, UserHandler: HandlerHookLog})
+
+	// aggregation and maintenance
+	c.Register(CommandInfo{Name: "agg", Group: groupAggregation, Summary: "fetch feeds in a loop", Usage: "<time_between_reqs/1h,1m,1s>", MinArgs: 1, MaxArgs: 1, Handler: HandlerAgg})
+	c.Register(CommandInfo{Name: "daemon", Group: groupAggregation, Summary: "fetch feeds like agg while serving /metrics and /healthz", Usage: "<time_between_reqs/1h,1m,1s> [listen_addr]", MinArgs: 1, MaxArgs: 2, Handler: HandlerDaemon})
+	c.Register(CommandInfo{Name: "fetch", Group: groupAggregation, Summary: "fetch one feed, or every followed one, right now", Usage: "[feed_url]", MaxArgs: 1, Flags: fetchFlags, UserHandler: HandlerFetch})
+	c.Register(CommandInfo{Name: "retention", Group: groupAggregation, Summary: "show or set how long posts of a feed are kept", Usage: retentionUsage, MaxArgs: 4, Flags: retentionFlags, UserHandler: HandlerRetention})
+	c.Register(CommandInfo{Name: "prune", Group: groupAggregation, Summary: "delete posts past their retention in the feeds you manage", Usage: "[feed_url]", MaxArgs: 1, Flags: pruneFlags, UserHandler: HandlerPrune})
+	c.Register(CommandInfo{Name: "migrate", Group: groupAggregation, Summary: "apply or roll back database migrations", Usage: migrateUsage, MinArgs: 1, MaxArgs: 2, Handler: HandlerMigrate})
+
+	c.Register(CommandInfo{Name: "help", Group: groupHelp, Summary: "list commands or describe one", Usage: "[command]", MaxArgs: 1, Handler: c.handlerHelp})
+
+	return c
+}
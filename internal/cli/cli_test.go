@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+)
+
+// run runs the command line args like gator does, returning what it printed
+func run(t *testing.T, s *service.State, args ...string) (string, error) {
+	t.Helper()
+
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("couldn't capture output: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = w
+	defer func() { os.Stdout = stdout }()
+
+	printed := make(chan string)
+	go func() {
+		out, _ := io.ReadAll(r)
+		printed <- string(out)
+	}()
+
+	err = NewCommands().Run(s, Command{Name: args[0], Args: args[1:]})
+	w.Close()
+	return <-printed, err
+}
+
+// mustRun is run for commands which have to succeed
+func mustRun(t *testing.T, s *service.State, args ...string) string {
+	t.Helper()
+
+	out, err := run(t, s, args...)
+	if err != nil {
+		t.Fatalf("gator %v: %v", strings.Join(args, " "), err)
+	}
+	return out
+}
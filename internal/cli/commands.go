@@ -6,58 +6,23 @@ import (
 	"fmt"
 	"grysha11/BlogAggregator/internal/database"
 	"grysha11/BlogAggregator/internal/service"
-	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
-type Command struct {
-	Name		string
-	Args		[]string
-}
-
-type Commands struct {
-	knownCommands	map[string]func(*service.State, Command) error
-}
-
-func (c *Commands) Run(s *service.State, cmd Command) error {
-	var err error
-
-	if fun, ok := c.knownCommands[cmd.Name]; ok {
-		err = fun(s, cmd)
-	} else {
-		err = fmt.Errorf("unknown command")
-	}
-
-	if err != nil {
-		return err
-	}
-
-	return nil
-}
-
-func (c *Commands) Register(name string, f func(*service.State, Command) error) {
-	if c.knownCommands == nil {
-		c.knownCommands = make(map[string]func(*service.State, Command) error)
-	}
-	c.knownCommands[name] = f
-}
-
 func HandlerLogin(s *service.State, cmd Command) error {
-	if len(cmd.Args) != 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: %v\nUsage: login <argument>", cmd.Name)
-	}
-
 	checkExist, err := s.DB.GetUserByName(context.Background(), cmd.Args[0])
-	if checkExist.ID == uuid.Nil {
+	if err == sql.ErrNoRows || checkExist.ID == uuid.Nil {
 		return fmt.Errorf("user don't exist")
 	}
 	if err != nil {
 		return err
 	}
 
-	if err := s.Config.SetUser(cmd.Args[0]); err != nil {
+	if err := s.Config.SetUser(checkExist.Name); err != nil {
 		return err
 	}
 
@@ -66,10 +31,6 @@ func HandlerLogin(s *service.State, cmd Command) error {
 }
 
 func HandlerRegister(s *service.State, cmd Command) error {
-	if len(cmd.Args) != 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: %v\nUsage: register <argument>", cmd.Name)
-	}
-
 	checkDup, err := s.DB.GetUserByName(context.Background(), cmd.Args[0])
 	if err == nil && checkDup.ID != uuid.Nil {
 		return fmt.Errorf("user already exists, exiting now...")
@@ -78,11 +39,18 @@ func HandlerRegister(s *service.State, cmd Command) error {
 		return err
 	}
 
+	// the first user of a fresh install administers it
+	users, err := s.DB.GetAllUsers(context.Background())
+	if err != nil {
+		return err
+	}
+
 	user, err := s.DB.CreateUser(context.Background(), database.CreateUserParams{
 		ID: uuid.New(),
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 		Name: cmd.Args[0],
+		IsAdmin: len(users) == 0,
 	})
 	if err != nil {
 		return err
@@ -115,12 +83,15 @@ func HandlerUsers(s *service.State, cmd Command) error {
 	}
 
 	if len(users) == 0 {
-		fmt.Printf("There are no users yet!")
+		fmt.Printf("There are no users yet!\n")
 		return nil
 	}
 
 	for _, user := range users {
 		fmt.Printf("* %v", user.Name)
+		if user.IsAdmin {
+			fmt.Printf(" (admin)")
+		}
 		if user.Name == s.Config.CurrentUsername {
 			fmt.Printf(" (current)")
 		}
@@ -131,13 +102,9 @@ func HandlerUsers(s *service.State, cmd Command) error {
 }
 
 func HandlerAgg(s *service.State, cmd Command) error {
-	if len(cmd.Args) != 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: <%v>\nUsage: agg <time_between_reqs/1h,1m,1s>", cmd.Name)
-	}
-
 	timeBetweenReqs, err := time.ParseDuration(cmd.Args[0])
 	if err != nil {
-		return fmt.Errorf("error during parsing time: %v\nUsage: agg <time_between_reqs/1h,1m,1s>", err)
+		return cmd.usageError("error during parsing time: %v", err)
 	}
 
 	fmt.Printf("Collecting feeds every %v\n", timeBetweenReqs)
@@ -149,14 +116,112 @@ func HandlerAgg(s *service.State, cmd Command) error {
 	}
 }
 
-//TODO add checker for dups of feeds
+const fetchWorkers = 8
+
+var fetchFlags = []Flag{
+	{Name: "mine", Kind: FlagBool, Summary: "fetch every feed you follow instead of one"},
+}
+
+func HandlerFetch(s *service.State, cmd Command, user database.User) error {
+	if cmd.flagBool("mine") == (len(cmd.Args) == 1) {
+		return cmd.usageError("either a feed_url or --mine is needed")
+	}
+
+	var feeds []database.Feed
+	if cmd.flagBool("mine") {
+		mine, err := s.DB.GetFeedsForUser(context.Background(), user.ID)
+		if err != nil {
+			return err
+		}
+		if len(mine) == 0 {
+			fmt.Printf("You don't have any feeds yet!\n")
+			return nil
+		}
+		feeds = mine
+	} else {
+		feed, err := getFeed(s, cmd.Args[0])
+		if err != nil {
+			return err
+		}
+		feeds = append(feeds, feed)
+	}
+
+	// the fetcher keeps hosts from being hammered, so feeds can be fetched in parallel
+	type result struct {
+		inserted	int
+		err			error
+	}
+	results := make([]result, len(feeds))
+
+	var wg sync.WaitGroup
+	workers := make(chan struct{}, fetchWorkers)
+	for i, feed := range feeds {
+		wg.Add(1)
+		workers <- struct{}{}
+		go func() {
+			defer wg.Done()
+			defer func() { <-workers }()
+			inserted, err := service.ScrapeFeed(s, feed)
+			results[i] = result{inserted: inserted, err: err}
+		}()
+	}
+	wg.Wait()
+
+	failed := 0
+	for i, feed := range feeds {
+		if results[i].err != nil {
+			failed++
+			fmt.Printf("\t* %v: %v\n", feed.Name, results[i].err)
+			continue
+		}
+		fmt.Printf("\t* %v: %v new posts\n", feed.Name, results[i].inserted)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%v of %v feeds couldn't be fetched", failed, len(feeds))
+	}
+	return nil
+}
+
+// followFlags are shared by the commands which start following a feed
+var followFlags = []Flag{
+	{Name: "folder", Kind: FlagString, Value: "name", Summary: "put the feed into a folder right away"},
+}
+
+// followFolder returns the folder given with --folder, nil when there is none
+func followFolder(s *service.State, cmd Command, user database.User) (*database.Folder, error) {
+	name := cmd.flagString("folder")
+	if name == "" {
+		return nil, nil
+	}
+	folder, err := getFolder(s, user, name)
+	if err != nil {
+		return nil, err
+	}
+	return &folder, nil
+}
+
+func addToFolder(s *service.State, followID uuid.UUID, folder *database.Folder) error {
+	if folder == nil {
+		return nil
+	}
+	return s.DB.AddFollowToFolder(context.Background(), database.AddFollowToFolderParams{
+		FeedFollowID: followID,
+		FolderID: folder.ID,
+	})
+}
 
 func HandlerAddFeed(s *service.State, cmd Command, user database.User) error {
-	if len(cmd.Args) != 2 {
-		return fmt.Errorf("incorrect amount of arguments in command call: <%v>\nUsage: addfeed <feed_name> <feed_url>", cmd.Name)
+	feedURL, err := service.CanonicalURL(cmd.Args[1])
+	if err != nil {
+		return err
+	}
+	folder, err := followFolder(s, cmd, user)
+	if err != nil {
+		return err
 	}
 
-	checkDup, err := s.DB.GetFeedByURL(context.Background(), cmd.Args[1])
+	checkDup, err := service.FindFeed(s, cmd.Args[1])
 	if err == nil && checkDup.ID != uuid.Nil {
 		return fmt.Errorf("feed already exists, exiting now...")
 	}
@@ -169,8 +234,8 @@ func HandlerAddFeed(s *service.State, cmd Command, user database.User) error {
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
 		Name: cmd.Args[0],
-		Url: cmd.Args[1],
-		UserID: user.ID,
+		Url: feedURL,
+		UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
 	})
 	if err != nil {
 		return err
@@ -183,6 +248,12 @@ func HandlerAddFeed(s *service.State, cmd Command, user database.User) error {
 		UserID: user.ID,
 		FeedID: feed.ID,
 	})
+	if err != nil {
+		return err
+	}
+	if err := addToFolder(s, follow.ID, folder); err != nil {
+		return err
+	}
 
 	fmt.Printf("Feed was created: %+v\n", follow)
 	return nil
@@ -200,24 +271,28 @@ func HandlerFeeds(s *service.State, cmd Command) error {
 	}
 
 	for _, feed := range feeds {
-		user, err := s.DB.GetUserByID(context.Background(), feed.UserID)
-		if err != nil {
-			return err
+		owner := "(no owner)"
+		if feed.UserID.Valid {
+			user, err := s.DB.GetUserByID(context.Background(), feed.UserID.UUID)
+			if err != nil {
+				return err
+			}
+			owner = user.Name
 		}
-		fmt.Printf("*\t%v\n\t %v\n\t %v\n", feed.Name, feed.Url, user.Name)
+		fmt.Printf("*\t%v\n\t %v\n\t %v\n", feed.Name, feed.Url, owner)
 	}
 
 	return nil
 }
 
 func HandlerFollow(s *service.State, cmd Command, user database.User) error {
-	if len(cmd.Args) != 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: <%v>\nUsage: follow <feed_url>", cmd.Name)
+	feed, err := getFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
 	}
-
-	feed, err := s.DB.GetFeedByURL(context.Background(), cmd.Args[0])
+	folder, err := followFolder(s, cmd, user)
 	if err != nil {
-		return fmt.Errorf("Feed doesn't exist: %v", err)
+		return err
 	}
 
 	follow, err := s.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
@@ -230,6 +305,9 @@ func HandlerFollow(s *service.State, cmd Command, user database.User) error {
 	if err != nil {
 		return err
 	}
+	if err := addToFolder(s, follow.ID, folder); err != nil {
+		return err
+	}
 
 	fmt.Printf("Feed:\t%v\nUser:\t%v\n", follow.FeedName, follow.UserName)
 	return nil
@@ -246,62 +324,145 @@ func HandlerFollowing(s *service.State, cmd Command, user database.User) error {
 		return nil
 	}
 
+	folders, err := s.DB.GetFoldersForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
 	fmt.Printf("Feeds which %v follows:\n", user.Name)
+	if len(folders) == 0 {
+		for _, feed := range feeds {
+			printFollow(feed)
+		}
+		return nil
+	}
+
+	followFolders, err := s.DB.GetFollowFoldersForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	byFolder := make(map[uuid.UUID][]uuid.UUID)
+	filed := make(map[uuid.UUID]bool)
+	for _, ff := range followFolders {
+		byFolder[ff.FolderID] = append(byFolder[ff.FolderID], ff.FeedFollowID)
+		filed[ff.FeedFollowID] = true
+	}
+
+	follows := make(map[uuid.UUID]database.GetFeedFollowsForUserRow)
+	for _, feed := range feeds {
+		follows[feed.ID] = feed
+	}
+
+	for _, folder := range folders {
+		fmt.Printf("  %v/\n", folder.Name)
+		if len(byFolder[folder.ID]) == 0 {
+			fmt.Printf("\t(empty)\n")
+		}
+		for _, followID := range byFolder[folder.ID] {
+			feed := follows[followID]
+			printFollow(feed)
+		}
+	}
+
+	var unfiled []database.GetFeedFollowsForUserRow
 	for _, feed := range feeds {
-		fmt.Printf("\t* %v\n", feed.FeedName)
+		if !filed[feed.ID] {
+			unfiled = append(unfiled, feed)
+		}
+	}
+	if len(unfiled) > 0 {
+		fmt.Printf("  Unfiled:\n")
+		for _, feed := range unfiled {
+			printFollow(feed)
+		}
 	}
 
 	return nil
 }
 
+func printFollow(feed database.GetFeedFollowsForUserRow) {
+	fmt.Printf("\t* %v (%v unread)\n", feed.FeedName, feed.UnreadCount)
+	if feed.DisplayName.Valid {
+		fmt.Printf("\t  Feed: %v\n", feed.OriginalName)
+	}
+	if feed.Notes.Valid {
+		fmt.Printf("\t  Notes: %v\n", feed.Notes.String)
+	}
+}
+
 func HandlerUnfollow(s *service.State, cmd Command, user database.User) error {
-	if len(cmd.Args) != 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: <%v>\nUsage: unfollow <feed_url>", cmd.Name)
+	feed, err := getFeed(s, cmd.Args[0])
+	if err != nil {
+		return err
 	}
 
-	err := s.DB.DeleteFeedFollowByUrl(context.Background(), database.DeleteFeedFollowByUrlParams{
+	err = s.DB.DeleteFeedFollowByUrl(context.Background(), database.DeleteFeedFollowByUrlParams{
 		UserID: user.ID,
-		Url: cmd.Args[0],
+		Url: feed.Url,
 	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Feed was unfollowed: %v\n", cmd.Args[0])
+	fmt.Printf("Feed was unfollowed: %v\n", feed.Url)
 	return nil
 }
 
-func HandlerBrowse(s *service.State, cmd Command, user database.User) error {
-	var limit int32
-	limit = 2
+// HandlerRenameFollow gives a followed feed the user's own name, "-" brings the feed's name back
+func HandlerRenameFollow(s *service.State, cmd Command, user database.User) error {
+	follow, err := getFollow(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
 
-	if len(cmd.Args) > 1 {
-		return fmt.Errorf("incorrect amount of arguments in command call: <%v>\nUsage: browse *Optional:<limit>", cmd.Name)
+	name := sql.NullString{}
+	if cmd.Args[1] != "-" {
+		name = sql.NullString{String: cmd.Args[1], Valid: true}
 	}
 
-	if len(cmd.Args) == 1 {
-		if manualLimit, err := strconv.Atoi(cmd.Args[0]); err == nil {
-			limit = int32(manualLimit)
-		} else {
-			return fmt.Errorf("invalid limit provided: %v", cmd.Args[0])
-		}
+	_, err = s.DB.SetFeedFollowName(context.Background(), database.SetFeedFollowNameParams{
+		ID: follow.ID,
+		DisplayName: name,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
 	}
 
-	posts, err := s.DB.GetPostsForUser(context.Background(), database.GetPostsForUserParams{
-		UserID: user.ID,
-		Limit: limit,
+	if name.Valid {
+		fmt.Printf("Feed is now shown as: %v\n", name.String)
+	} else {
+		fmt.Printf("Feed name was reset: %v\n", cmd.Args[0])
+	}
+	return nil
+}
+
+// HandlerNoteFollow keeps the user's notes about a followed feed, "-" removes them
+func HandlerNoteFollow(s *service.State, cmd Command, user database.User) error {
+	follow, err := getFollow(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	notes := sql.NullString{}
+	if text := strings.Join(cmd.Args[1:], " "); text != "-" {
+		notes = sql.NullString{String: text, Valid: true}
+	}
+
+	_, err = s.DB.SetFeedFollowNotes(context.Background(), database.SetFeedFollowNotesParams{
+		ID: follow.ID,
+		Notes: notes,
+		UpdatedAt: time.Now().UTC(),
 	})
 	if err != nil {
 		return err
 	}
 
-	fmt.Printf("Found %v posts for user %v:\n", len(posts), user.Name)
-	for _, post := range posts {
-		fmt.Printf("--- %s ---\n", post.Title)
-		fmt.Printf("    %v\n", post.Description.String)
-		fmt.Printf("    Link: %s\n", post.Url)
-		fmt.Println("=====================================")
+	if notes.Valid {
+		fmt.Printf("Notes were saved: %v\n", cmd.Args[0])
+	} else {
+		fmt.Printf("Notes were removed: %v\n", cmd.Args[0])
 	}
-	
 	return nil
 }
@@ -0,0 +1,65 @@
+package cli
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/servicetest"
+)
+
+func TestAddFeedAndFollow(t *testing.T) {
+	s := servicetest.NewState(t)
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "addfeed", "blog", "http://Example.com/feed/")
+
+	feed, err := s.DB.GetFeedByURL(context.Background(), "http://example.com/feed")
+	if err != nil {
+		t.Fatalf("feed isn't stored under its canonical url: %v", err)
+	}
+	alice, err := s.DB.GetUserByName(context.Background(), "alice")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	if !alice.IsAdmin {
+		t.Errorf("first user isn't an admin")
+	}
+	if feed.UserID.UUID != alice.ID {
+		t.Errorf("feed is owned by %v, want alice", feed.UserID.UUID)
+	}
+
+	if _, err := run(t, s, "addfeed", "again", "http://example.com/feed"); err == nil {
+		t.Errorf("adding the same feed twice succeeded")
+	}
+
+	mustRun(t, s, "register", "bob")
+	mustRun(t, s, "follow", "http://example.com/feed")
+	bob, err := s.DB.GetUserByName(context.Background(), "bob")
+	if err != nil {
+		t.Fatalf("GetUserByName: %v", err)
+	}
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), bob.ID)
+	if err != nil {
+		t.Fatalf("GetFeedFollowsForUser: %v", err)
+	}
+	if len(follows) != 1 || follows[0].FeedID != feed.ID {
+		t.Fatalf("bob follows %v, want the blog feed only", follows)
+	}
+
+	_, err = run(t, s, "follow", "http://example.com/missing")
+	if err == nil || err.Error() != "feed doesn't exist: http://example.com/missing" {
+		t.Errorf("following a missing feed = %v", err)
+	}
+
+	out := mustRun(t, s, "following")
+	if !strings.Contains(out, "blog") {
+		t.Errorf("following doesn't list the feed:\n%v", out)
+	}
+}
+
+func TestCommandNeedsLogin(t *testing.T) {
+	s := servicetest.NewState(t)
+	if _, err := run(t, s, "addfeed", "blog", "http://example.com/feed"); err == nil {
+		t.Fatalf("addfeed without a logged in user succeeded")
+	}
+}
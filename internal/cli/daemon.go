@@ -0,0 +1,107 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"grysha11/BlogAggregator/internal/metrics"
+	"grysha11/BlogAggregator/internal/service"
+)
+
+const defaultMetricsAddr = "localhost:2112"
+
+// HandlerDaemon runs the same loop as agg, but also serves /metrics and /healthz
+// so the aggregator can be watched while it runs in the background
+func HandlerDaemon(s *service.State, cmd Command) error {
+	timeBetweenReqs, err := time.ParseDuration(cmd.Args[0])
+	if err != nil {
+		return cmd.usageError("error during parsing time: %v", err)
+	}
+
+	addr := defaultMetricsAddr
+	if len(cmd.Args) == 2 {
+		addr = cmd.Args[1]
+	}
+
+	s.Metrics = metrics.New()
+
+	var started atomic.Bool
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", s.Metrics.Handler(func() {
+		updateQueueLag(s)
+	}))
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		if !started.Load() {
+			http.Error(w, "aggregator is not running yet", http.StatusServiceUnavailable)
+			return
+		}
+		_, err := s.DB.GetNextFeedToFetch(r.Context())
+		if err != nil && err != sql.ErrNoRows {
+			http.Error(w, fmt.Sprintf("database is unavailable: %v", err), http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprintln(w, "ok")
+	})
+
+	server := &http.Server{
+		Addr: addr,
+		Handler: mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			serverErr <- err
+		}
+	}()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	s.Logger.Info("daemon started", "interval", timeBetweenReqs, "metrics_url", "http://"+addr+"/metrics")
+
+	ticker := time.NewTicker(timeBetweenReqs)
+	defer ticker.Stop()
+
+	for {
+		service.ScrapeFeeds(s)
+		started.Store(true)
+
+		select {
+		case <-ticker.C:
+		case err := <-serverErr:
+			return fmt.Errorf("metrics server stopped: %v", err)
+		case <-ctx.Done():
+			s.Logger.Info("daemon stopping")
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			return server.Shutdown(shutdownCtx)
+		}
+	}
+}
+
+func updateQueueLag(s *service.State) {
+	feed, err := s.DB.GetNextFeedToFetch(context.Background())
+	if err != nil {
+		if err != sql.ErrNoRows {
+			s.Metrics.IncDBErrors()
+		}
+		return
+	}
+
+	since := feed.CreatedAt
+	if feed.LastFetchedAt.Valid {
+		since = feed.LastFetchedAt.Time
+	}
+	s.Metrics.SetQueueLag(time.Since(since))
+}
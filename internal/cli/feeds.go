@@ -0,0 +1,218 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// getFeed looks a feed up by its url, telling the user plainly when there is none
+func getFeed(s *service.State, feedURL string) (database.Feed, error) {
+	feed, err := service.FindFeed(s, feedURL)
+	if err == sql.ErrNoRows {
+		return database.Feed{}, fmt.Errorf("feed doesn't exist: %v", feedURL)
+	}
+	return feed, err
+}
+
+// getManagedFeed returns the feed if user is allowed to change it
+func getManagedFeed(s *service.State, user database.User, feedURL string) (database.Feed, error) {
+	feed, err := getFeed(s, feedURL)
+	if err != nil {
+		return database.Feed{}, err
+	}
+
+	if !service.CanManageFeed(user, feed) {
+		return database.Feed{}, service.ErrNotFeedOwner
+	}
+	return feed, nil
+}
+
+// HandlerDeleteFeed removes a feed for everyone, together with its posts and follows
+func HandlerDeleteFeed(s *service.State, cmd Command, user database.User) error {
+	feed, err := getManagedFeed(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := s.DB.DeleteFeed(context.Background(), feed.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed was deleted: %v\n", feed.Name)
+	return nil
+}
+
+func HandlerRenameFeed(s *service.State, cmd Command, user database.User) error {
+	feed, err := getManagedFeed(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	renamed, err := s.DB.RenameFeed(context.Background(), database.RenameFeedParams{
+		ID: feed.ID,
+		Name: cmd.Args[1],
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed was renamed: %v -> %v\n", feed.Name, renamed.Name)
+	return nil
+}
+
+func HandlerSetFeedURL(s *service.State, cmd Command, user database.User) error {
+	feed, err := getManagedFeed(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	feedURL, err := service.CanonicalURL(cmd.Args[1])
+	if err != nil {
+		return err
+	}
+
+	checkDup, err := service.FindFeed(s, feedURL)
+	if err == nil && checkDup.ID != uuid.Nil && checkDup.ID != feed.ID {
+		return fmt.Errorf("feed already exists: %v", feedURL)
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	moved, err := s.DB.SetFeedURL(context.Background(), database.SetFeedURLParams{
+		ID: feed.ID,
+		Url: feedURL,
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed %v now lives at: %v\n", moved.Name, moved.Url)
+	return nil
+}
+
+func HandlerTransferFeed(s *service.State, cmd Command, user database.User) error {
+	feed, err := getManagedFeed(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	owner, err := s.DB.GetUserByName(context.Background(), cmd.Args[1])
+	if err != nil {
+		return fmt.Errorf("user don't exist")
+	}
+
+	_, err = s.DB.SetFeedOwner(context.Background(), database.SetFeedOwnerParams{
+		ID: feed.ID,
+		UserID: uuid.NullUUID{UUID: owner.ID, Valid: true},
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed %v is now owned by %v\n", feed.Name, owner.Name)
+	return nil
+}
+
+// HandlerSetAdmin lets an admin grant or take away admin rights
+func HandlerSetAdmin(s *service.State, cmd Command, user database.User) error {
+	if len(cmd.Args) != 2 || (cmd.Args[1] != "yes" && cmd.Args[1] != "no") {
+		return cmd.usageError("incorrect arguments in command call: <%v>", cmd.Name)
+	}
+
+	if !user.IsAdmin {
+		return fmt.Errorf("only admins can do that")
+	}
+
+	target, err := s.DB.GetUserByName(context.Background(), cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("user don't exist")
+	}
+
+	if target.ID == user.ID && cmd.Args[1] == "no" {
+		return fmt.Errorf("you can't take admin rights from yourself")
+	}
+
+	_, err = s.DB.SetUserAdmin(context.Background(), database.SetUserAdminParams{
+		ID: target.ID,
+		IsAdmin: cmd.Args[1] == "yes",
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	if cmd.Args[1] == "yes" {
+		fmt.Printf("%v is an admin now\n", target.Name)
+	} else {
+		fmt.Printf("%v is no longer an admin\n", target.Name)
+	}
+	return nil
+}
+
+// HandlerFeedStatus shows when the followed feeds were fetched and posted to last
+func HandlerFeedStatus(s *service.State, cmd Command, user database.User) error {
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Args) == 1 {
+		feed, err := getFeed(s, cmd.Args[0])
+		if err != nil {
+			return err
+		}
+		follows = slices.DeleteFunc(follows, func(follow database.GetFeedFollowsForUserRow) bool {
+			return follow.FeedID != feed.ID
+		})
+		if len(follows) == 0 {
+			return fmt.Errorf("you don't follow this feed: %v", cmd.Args[0])
+		}
+	}
+
+	if len(follows) == 0 {
+		fmt.Printf("You don't have any feeds yet!\n")
+		return nil
+	}
+
+	loc := service.Location(user)
+	fmt.Printf("Feeds which %v follows (times in %v):\n", user.Name, timezoneName(user))
+	for _, follow := range follows {
+		feed, err := s.DB.GetFeedByURL(context.Background(), follow.FeedUrl)
+		if err != nil {
+			return err
+		}
+
+		// what the feed delivered, mute rules don't hide anything here
+		latest, err := s.DB.GetLatestPostForFeed(context.Background(), feed.ID)
+		if err != nil && err != sql.ErrNoRows {
+			return err
+		}
+
+		fetched := "never"
+		if feed.LastFetchedAt.Valid {
+			fetched = service.FormatTime(feed.LastFetchedAt.Time, loc)
+		}
+		posted := "nothing yet"
+		if err == nil {
+			posted = service.FormatTime(latest.PublishedAt, loc)
+		}
+
+		fmt.Printf("\t* %v (%v unread)\n", follow.FeedName, follow.UnreadCount)
+		fmt.Printf("\t  Last fetched: %v\n", fetched)
+		fmt.Printf("\t  Latest post: %v\n", posted)
+	}
+
+	return nil
+}
@@ -0,0 +1,186 @@
+package cli
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FlagKind int
+
+// kinds of values a flag takes
+const (
+	FlagBool	FlagKind = iota
+	FlagString
+	FlagInt
+	// a day written as YYYY-MM-DD, it is placed in the user's timezone when read
+	FlagDate
+)
+
+// Flag is an option a command declares, given as --name value, --name=value
+// or just --name for bool flags. Flags may come anywhere between the positional
+// arguments, everything after a lone "--" is positional.
+type Flag struct {
+	Name		string
+	Kind		FlagKind
+	// Value names the value in the usage, like "n" in "--limit n"
+	Value		string
+	// Default is used when the flag isn't given, written like on the command line
+	Default		string
+	// Choices are the only values accepted when set
+	Choices		[]string
+	Summary		string
+}
+
+func (f Flag) usage() string {
+	if f.Kind == FlagBool {
+		return "--" + f.Name
+	}
+	value := f.Value
+	switch {
+	case len(f.Choices) > 0:
+		value = strings.Join(f.Choices, "|")
+	case value == "" && f.Kind == FlagDate:
+		value = "YYYY-MM-DD"
+	case value == "":
+		value = "value"
+	}
+	return "--" + f.Name + " " + value
+}
+
+// parse turns the text of a flag into its typed value
+func (f Flag) parse(value string) (any, error) {
+	if len(f.Choices) > 0 && !slices.Contains(f.Choices, value) {
+		return nil, fmt.Errorf("invalid value for --%v: %v (expected one of %v)", f.Name, value, strings.Join(f.Choices, ", "))
+	}
+
+	switch f.Kind {
+	case FlagBool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%v: %v (expected true or false)", f.Name, value)
+		}
+		return b, nil
+	case FlagInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%v: %v (expected a number)", f.Name, value)
+		}
+		return n, nil
+	case FlagDate:
+		day, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for --%v: %v (expected a date like 2006-01-02)", f.Name, value)
+		}
+		return day, nil
+	}
+	return value, nil
+}
+
+func (f Flag) defaultValue() any {
+	if f.Default == "" {
+		switch f.Kind {
+		case FlagBool:
+			return false
+		case FlagInt:
+			return 0
+		case FlagDate:
+			return time.Time{}
+		}
+		return ""
+	}
+
+	value, err := f.parse(f.Default)
+	if err != nil {
+		panic(fmt.Sprintf("flag --%v has a broken default: %v", f.Name, err))
+	}
+	return value
+}
+
+// parseFlags splits cmd.Args into the declared flags and the positional arguments left in cmd.Args
+func (cmd *Command) parseFlags(flags []Flag) error {
+	cmd.flags = make(map[string]any, len(flags))
+	cmd.given = make(map[string]bool)
+	for _, f := range flags {
+		cmd.flags[f.Name] = f.defaultValue()
+	}
+
+	var args []string
+	for i := 0; i < len(cmd.Args); i++ {
+		arg := cmd.Args[i]
+		if arg == "--" {
+			args = append(args, cmd.Args[i+1:]...)
+			break
+		}
+		if !strings.HasPrefix(arg, "--") {
+			args = append(args, arg)
+			continue
+		}
+
+		name, value, hasValue := strings.Cut(strings.TrimPrefix(arg, "--"), "=")
+		var flag *Flag
+		for j := range flags {
+			if flags[j].Name == name {
+				flag = &flags[j]
+			}
+		}
+		if flag == nil {
+			return cmd.usageError("unknown option: --%v", name)
+		}
+		if cmd.given[name] {
+			return cmd.usageError("option given twice: --%v", name)
+		}
+
+		if !hasValue {
+			if flag.Kind == FlagBool {
+				value = "true"
+			} else if i+1 < len(cmd.Args) {
+				i++
+				value = cmd.Args[i]
+			} else {
+				return cmd.usageError("missing value for --%v", name)
+			}
+		}
+
+		parsed, err := flag.parse(value)
+		if err != nil {
+			return cmd.usageError("%v", err)
+		}
+		cmd.flags[name] = parsed
+		cmd.given[name] = true
+	}
+
+	cmd.Args = args
+	return nil
+}
+
+// flagGiven tells whether the flag was on the command line rather than left at its default
+func (cmd Command) flagGiven(name string) bool {
+	return cmd.given[name]
+}
+
+func (cmd Command) flagBool(name string) bool {
+	b, _ := cmd.flags[name].(bool)
+	return b
+}
+
+func (cmd Command) flagString(name string) string {
+	str, _ := cmd.flags[name].(string)
+	return str
+}
+
+func (cmd Command) flagInt(name string) int {
+	n, _ := cmd.flags[name].(int)
+	return n
+}
+
+// flagDate returns the start of the given day in loc, ok is false when the flag wasn't given
+func (cmd Command) flagDate(name string, loc *time.Location) (time.Time, bool) {
+	day, ok := cmd.flags[name].(time.Time)
+	if !ok || day.IsZero() {
+		return time.Time{}, false
+	}
+	return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, loc), true
+}
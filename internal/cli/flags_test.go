@@ -0,0 +1,91 @@
+package cli
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+var testFlags = []Flag{
+	{Name: "limit", Kind: FlagInt, Default: "2"},
+	{Name: "unread", Kind: FlagBool},
+	{Name: "title", Kind: FlagString},
+	{Name: "sort", Kind: FlagString, Default: "published", Choices: []string{"published", "fetched"}},
+	{Name: "since", Kind: FlagDate},
+}
+
+func TestParseFlags(t *testing.T) {
+	tests := []struct {
+		args	string
+		// want is the positional arguments left over
+		want	[]string
+		limit	int
+		unread	bool
+		title	string
+		sort	string
+		since	string
+	}{
+		{args: "", limit: 2, sort: "published"},
+		{args: "a b", want: []string{"a", "b"}, limit: 2, sort: "published"},
+		{args: "--limit 5", limit: 5, sort: "published"},
+		{args: "--limit=5", limit: 5, sort: "published"},
+		{args: "--limit -3", limit: -3, sort: "published"},
+		{args: "--unread", limit: 2, unread: true, sort: "published"},
+		{args: "--unread=false", limit: 2, sort: "published"},
+		{args: "a --title go b", want: []string{"a", "b"}, limit: 2, title: "go", sort: "published"},
+		{args: "--title=a=b", limit: 2, title: "a=b", sort: "published"},
+		{args: "--sort fetched", limit: 2, sort: "fetched"},
+		{args: "--since 2024-03-01", limit: 2, sort: "published", since: "2024-03-01"},
+		{args: "a -- --limit 5", want: []string{"a", "--limit", "5"}, limit: 2, sort: "published"},
+	}
+	for _, test := range tests {
+		cmd := Command{Name: "test", Args: strings.Fields(test.args)}
+		if err := cmd.parseFlags(testFlags); err != nil {
+			t.Errorf("parseFlags(%q): %v", test.args, err)
+			continue
+		}
+
+		var since string
+		if day, ok := cmd.flagDate("since", time.UTC); ok {
+			since = day.Format(time.DateOnly)
+		}
+		if !slices.Equal(cmd.Args, test.want) || cmd.flagInt("limit") != test.limit || cmd.flagBool("unread") != test.unread ||
+			cmd.flagString("title") != test.title || cmd.flagString("sort") != test.sort || since != test.since {
+			t.Errorf("parseFlags(%q) = args %q limit %v unread %v title %q sort %q since %q", test.args, cmd.Args,
+				cmd.flagInt("limit"), cmd.flagBool("unread"), cmd.flagString("title"), cmd.flagString("sort"), since)
+		}
+	}
+}
+
+func TestParseFlagsGiven(t *testing.T) {
+	cmd := Command{Name: "test", Args: []string{"--limit", "2"}}
+	if err := cmd.parseFlags(testFlags); err != nil {
+		t.Fatalf("parseFlags: %v", err)
+	}
+	if !cmd.flagGiven("limit") || cmd.flagGiven("sort") {
+		t.Errorf("flagGiven is %v for --limit and %v for --sort, want true and false", cmd.flagGiven("limit"), cmd.flagGiven("sort"))
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	tests := []struct {
+		args	string
+		err		string
+	}{
+		{args: "--color", err: "unknown option: --color"},
+		{args: "--limit 1 --limit 2", err: "option given twice: --limit"},
+		{args: "--limit", err: "missing value for --limit"},
+		{args: "--limit many", err: "invalid value for --limit: many (expected a number)"},
+		{args: "--unread=maybe", err: "invalid value for --unread: maybe (expected true or false)"},
+		{args: "--sort newest", err: "invalid value for --sort: newest (expected one of published, fetched)"},
+		{args: "--since yesterday", err: "invalid value for --since: yesterday (expected a date like 2006-01-02)"},
+	}
+	for _, test := range tests {
+		cmd := Command{Name: "test", Args: strings.Fields(test.args)}
+		err := cmd.parseFlags(testFlags)
+		if err == nil || !strings.Contains(err.Error(), test.err) {
+			t.Errorf("parseFlags(%q) = %v, want %q", test.args, err, test.err)
+		}
+	}
+}
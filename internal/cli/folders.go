@@ -0,0 +1,182 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+func getFolder(s *service.State, user database.User, name string) (database.Folder, error) {
+	folder, err := s.DB.GetFolderByName(context.Background(), database.GetFolderByNameParams{
+		UserID: user.ID,
+		Name: name,
+	})
+	if err == sql.ErrNoRows {
+		return database.Folder{}, fmt.Errorf("folder doesn't exist: %v", name)
+	}
+	return folder, err
+}
+
+func getFollow(s *service.State, user database.User, feedURL string) (database.FeedFollow, error) {
+	feed, err := service.FindFeed(s, feedURL)
+	if err == sql.ErrNoRows {
+		return database.FeedFollow{}, fmt.Errorf("you don't follow this feed: %v", feedURL)
+	}
+	if err != nil {
+		return database.FeedFollow{}, err
+	}
+
+	follow, err := s.DB.GetFeedFollowByURL(context.Background(), database.GetFeedFollowByURLParams{
+		UserID: user.ID,
+		Url: feed.Url,
+	})
+	if err == sql.ErrNoRows {
+		return database.FeedFollow{}, fmt.Errorf("you don't follow this feed: %v", feedURL)
+	}
+	return follow, err
+}
+
+func HandlerMkFolder(s *service.State, cmd Command, user database.User) error {
+	checkDup, err := s.DB.GetFolderByName(context.Background(), database.GetFolderByNameParams{
+		UserID: user.ID,
+		Name: cmd.Args[0],
+	})
+	if err == nil && checkDup.ID != uuid.Nil {
+		return fmt.Errorf("folder already exists: %v", cmd.Args[0])
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	folder, err := s.DB.CreateFolder(context.Background(), database.CreateFolderParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID: user.ID,
+		Name: cmd.Args[0],
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Folder was created: %v\n", folder.Name)
+	return nil
+}
+
+func HandlerRenameFolder(s *service.State, cmd Command, user database.User) error {
+	folder, err := getFolder(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if _, err := getFolder(s, user, cmd.Args[1]); err == nil {
+		return fmt.Errorf("folder already exists: %v", cmd.Args[1])
+	}
+
+	folder, err = s.DB.RenameFolder(context.Background(), database.RenameFolderParams{
+		ID: folder.ID,
+		Name: cmd.Args[1],
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Folder was renamed: %v -> %v\n", cmd.Args[0], folder.Name)
+	return nil
+}
+
+// HandlerRmFolder deletes a folder, the feeds in it stay followed
+func HandlerRmFolder(s *service.State, cmd Command, user database.User) error {
+	folder, err := getFolder(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if err := s.DB.DeleteFolder(context.Background(), folder.ID); err != nil {
+		return err
+	}
+
+	fmt.Printf("Folder was deleted: %v\n", folder.Name)
+	return nil
+}
+
+// HandlerTagFeed puts a followed feed into one more folder
+func HandlerTagFeed(s *service.State, cmd Command, user database.User) error {
+	follow, err := getFollow(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	folder, err := getFolder(s, user, cmd.Args[1])
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.AddFollowToFolder(context.Background(), database.AddFollowToFolderParams{
+		FeedFollowID: follow.ID,
+		FolderID: folder.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed was added to %v: %v\n", folder.Name, cmd.Args[0])
+	return nil
+}
+
+func HandlerUntagFeed(s *service.State, cmd Command, user database.User) error {
+	follow, err := getFollow(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	folder, err := getFolder(s, user, cmd.Args[1])
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.DB.RemoveFollowFromFolder(context.Background(), database.RemoveFollowFromFolderParams{
+		FeedFollowID: follow.ID,
+		FolderID: folder.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("feed isn't in %v: %v", folder.Name, cmd.Args[0])
+	}
+
+	fmt.Printf("Feed was removed from %v: %v\n", folder.Name, cmd.Args[0])
+	return nil
+}
+
+// HandlerMoveFeed takes a followed feed out of all its folders and puts it into the given one
+func HandlerMoveFeed(s *service.State, cmd Command, user database.User) error {
+	follow, err := getFollow(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+	folder, err := getFolder(s, user, cmd.Args[1])
+	if err != nil {
+		return err
+	}
+
+	if err := s.DB.RemoveFollowFromAllFolders(context.Background(), follow.ID); err != nil {
+		return err
+	}
+	err = s.DB.AddFollowToFolder(context.Background(), database.AddFollowToFolderParams{
+		FeedFollowID: follow.ID,
+		FolderID: folder.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Feed was moved to %v: %v\n", folder.Name, cmd.Args[0])
+	return nil
+}
@@ -0,0 +1,136 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/hooks"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var addHookFlags = []Flag{
+	{Name: "feed", Kind: FlagString, Value: "feed_url", Summary: "only fire for posts of one feed"},
+	{Name: "template", Kind: FlagString, Default: "json", Choices: hooks.Templates, Summary: "shape of the webhook body"},
+	{Name: "attempts", Kind: FlagInt, Value: "n", Default: "3", Summary: "how many times a failed delivery is tried"},
+}
+
+func HandlerAddHook(s *service.State, cmd Command, user database.User) error {
+	kind, target := cmd.Args[0], cmd.Args[1]
+	if kind != hooks.KindWebhook && kind != hooks.KindCommand {
+		return cmd.usageError("unknown hook kind: %v", kind)
+	}
+
+	params := database.CreateHookParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID: user.ID,
+		Kind: kind,
+		Target: target,
+		Template: cmd.flagString("template"),
+		MaxAttempts: int32(cmd.flagInt("attempts")),
+	}
+	if params.MaxAttempts < 1 {
+		return fmt.Errorf("invalid attempts provided: %v", params.MaxAttempts)
+	}
+
+	if feedURL := cmd.flagString("feed"); feedURL != "" {
+		feed, err := getFeed(s, feedURL)
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+
+	hook, err := s.DB.CreateHook(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Hook was created: %v\n", hook.ID)
+	if hook.Kind == hooks.KindCommand && !s.Config.AllowCommandHooks {
+		fmt.Printf("Note: command hooks only run where allow_command_hooks is set in the config\n")
+	}
+	return nil
+}
+
+func HandlerHooks(s *service.State, cmd Command, user database.User) error {
+	userHooks, err := s.DB.GetHooksForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(userHooks) == 0 {
+		fmt.Printf("You don't have any hooks yet!\n")
+		return nil
+	}
+
+	for _, hook := range userHooks {
+		scope := "all followed feeds"
+		if hook.FeedName.Valid {
+			scope = hook.FeedName.String
+		}
+		fmt.Printf("* %v\n\t%v: %v\n\tTemplate: %v, attempts: %v\n\tFeeds: %v\n", hook.ID, hook.Kind, hook.Target, hook.Template, hook.MaxAttempts, scope)
+	}
+
+	return nil
+}
+
+func HandlerDeleteHook(s *service.State, cmd Command, user database.User) error {
+	id, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid hook id provided: %v", cmd.Args[0])
+	}
+
+	deleted, err := s.DB.DeleteHook(context.Background(), database.DeleteHookParams{
+		ID: id,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("hook doesn't exist: %v", cmd.Args[0])
+	}
+
+	fmt.Printf("Hook was deleted: %v\n", id)
+	return nil
+}
+
+var hookLogFlags = []Flag{
+	{Name: "limit", Kind: FlagInt, Value: "n", Default: "20", Summary: "how many deliveries to show"},
+}
+
+func HandlerHookLog(s *service.State, cmd Command, user database.User) error {
+	limit := int32(cmd.flagInt("limit"))
+	if limit < 1 {
+		return fmt.Errorf("invalid limit provided: %v", limit)
+	}
+
+	deliveries, err := s.DB.GetHookDeliveriesForUser(context.Background(), database.GetHookDeliveriesForUserParams{
+		UserID: user.ID,
+		Limit: limit,
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(deliveries) == 0 {
+		fmt.Printf("No hooks were delivered yet!\n")
+		return nil
+	}
+
+	for _, delivery := range deliveries {
+		status := "ok"
+		if !delivery.Succeeded {
+			status = "failed: " + delivery.Error.String
+		}
+		fmt.Printf("* %v %v %v\n\tPost: %v\n\tAttempts: %v, %v\n", delivery.CreatedAt.In(service.Location(user)).Format(time.DateTime), delivery.HookKind, delivery.HookTarget, delivery.PostTitle, delivery.Attempts, status)
+	}
+
+	return nil
+}
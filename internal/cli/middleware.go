@@ -2,13 +2,22 @@ package cli
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"grysha11/BlogAggregator/internal/database"
     "grysha11/BlogAggregator/internal/service"
 )
 
 func MiddlewareLoggedIn(handler func(s *service.State, cmd Command, user database.User) error) func(*service.State, Command) error {
     return func(s *service.State, cmd Command) error {
+        if s.Config.CurrentUsername == "" {
+            return fmt.Errorf("you are not logged in, use login or register first")
+        }
+
         user, err := s.DB.GetUserByName(context.Background(), s.Config.CurrentUsername)
+        if err == sql.ErrNoRows {
+            return fmt.Errorf("logged in user %v doesn't exist anymore, use login or register", s.Config.CurrentUsername)
+        }
         if err != nil {
             return err
         }
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"grysha11/BlogAggregator/internal/migrations"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/pressly/goose/v3"
+)
+
+const migrateUsage = "up | down | status | to <version>"
+
+// HandlerMigrate applies or rolls back the migrations built into the binary
+func HandlerMigrate(s *service.State, cmd Command) error {
+	if s.Conn == nil {
+		return fmt.Errorf("there is no database to migrate")
+	}
+
+	p, err := migrations.New(s.Conn, s.Backend)
+	if err != nil {
+		return err
+	}
+	ctx := context.Background()
+
+	var results []*goose.MigrationResult
+	switch {
+	case cmd.Args[0] == "up" && len(cmd.Args) == 1:
+		results, err = p.Up(ctx)
+	case cmd.Args[0] == "down" && len(cmd.Args) == 1:
+		var result *goose.MigrationResult
+		result, err = p.Down(ctx)
+		if result != nil {
+			results = append(results, result)
+		}
+	case cmd.Args[0] == "status" && len(cmd.Args) == 1:
+		return printMigrationStatus(ctx, p)
+	case cmd.Args[0] == "to" && len(cmd.Args) == 2:
+		version, convErr := strconv.ParseInt(cmd.Args[1], 10, 64)
+		if convErr != nil || version < 0 {
+			return cmd.usageError("invalid version provided: %v", cmd.Args[1])
+		}
+		if latest := migrations.Expected(p); version > latest {
+			return cmd.usageError("there is no version %v, the latest one is %v", version, latest)
+		}
+
+		current, verErr := p.GetDBVersion(ctx)
+		if verErr != nil {
+			return verErr
+		}
+		if version >= current {
+			results, err = p.UpTo(ctx, version)
+		} else {
+			results, err = p.DownTo(ctx, version)
+		}
+	default:
+		return cmd.usageError("incorrect arguments in command call: <%v>", cmd.Name)
+	}
+
+	for _, result := range results {
+		fmt.Println(result)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("Nothing to migrate\n")
+	}
+	current, err := p.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Database is at version %v\n", current)
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, p *goose.Provider) error {
+	statuses, err := p.Status(ctx)
+	if err != nil {
+		return err
+	}
+
+	current, err := p.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, status := range statuses {
+		applied := "pending"
+		if status.State == goose.StateApplied {
+			applied = status.AppliedAt.Local().Format(time.DateTime)
+		}
+		fmt.Printf("  %-20v %v\n", applied, status.Source.Path)
+	}
+	fmt.Printf("Database is at version %v, gator needs version %v\n", current, migrations.Expected(p))
+	return nil
+}
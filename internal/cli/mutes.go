@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"fmt"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var muteFlags = []Flag{
+	{Name: "feed", Kind: FlagString, Value: "feed_url", Summary: "only hide posts of one feed"},
+}
+
+func HandlerMute(s *service.State, cmd Command, user database.User) error {
+	var feed *database.Feed
+	if feedURL := cmd.flagString("feed"); feedURL != "" {
+		found, err := getFeed(s, feedURL)
+		if err != nil {
+			return err
+		}
+		feed = &found
+	}
+
+	mute, err := service.CreateMute(s, user, cmd.Args[0], cmd.Args[1], feed)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Mute was created: %v\n", mute.ID)
+	return nil
+}
+
+func HandlerUnmute(s *service.State, cmd Command, user database.User) error {
+	id, err := uuid.Parse(cmd.Args[0])
+	if err != nil {
+		return fmt.Errorf("invalid mute id provided: %v", cmd.Args[0])
+	}
+
+	deleted, err := s.DB.DeleteMute(context.Background(), database.DeleteMuteParams{
+		ID: id,
+		UserID: user.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if deleted == 0 {
+		return fmt.Errorf("mute doesn't exist: %v", cmd.Args[0])
+	}
+
+	fmt.Printf("Mute was deleted: %v\n", id)
+	return nil
+}
+
+// HandlerMutes lists the mute rules of the user with how many posts each of them hides
+func HandlerMutes(s *service.State, cmd Command, user database.User) error {
+	mutes, err := service.UserMutes(s, user)
+	if err != nil {
+		return err
+	}
+
+	if len(mutes) == 0 {
+		fmt.Printf("You don't have any mutes yet!\n")
+		return nil
+	}
+
+	counts, err := service.MuteCounts(s, user, mutes)
+	if err != nil {
+		return err
+	}
+
+	for _, mute := range mutes {
+		scope := "all followed feeds"
+		if mute.FeedName.Valid {
+			scope = mute.FeedName.String
+		}
+		fmt.Printf("* %v\n\t%v: %v\n\tFeeds: %v\n\tHides %v posts\n", mute.ID, mute.Kind, mute.Pattern, scope, counts[mute.ID])
+	}
+
+	return nil
+}
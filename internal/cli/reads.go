@@ -0,0 +1,88 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// findPost looks a post up by its id or its link, whichever the user passed,
+// telling the user plainly when there is none
+func findPost(s *service.State, arg string) (database.Post, error) {
+	var post database.Post
+	var err error
+	if id, parseErr := uuid.Parse(arg); parseErr == nil {
+		post, err = s.DB.GetPostByID(context.Background(), id)
+	} else {
+		post, err = service.FindPostByURL(s, arg)
+	}
+	if err == sql.ErrNoRows {
+		return database.Post{}, fmt.Errorf("post doesn't exist: %v", arg)
+	}
+	return post, err
+}
+
+func HandlerRead(s *service.State, cmd Command, user database.User) error {
+	post, err := findPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		ReadAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Marked as read: %v\n", post.Title)
+	return nil
+}
+
+func HandlerUnread(s *service.State, cmd Command, user database.User) error {
+	post, err := findPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.MarkPostUnread(context.Background(), database.MarkPostUnreadParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Marked as unread: %v\n", post.Title)
+	return nil
+}
+
+func HandlerMarkAllRead(s *service.State, cmd Command, user database.User) error {
+	params := database.MarkAllPostsReadParams{
+		ReadAt: time.Now().UTC(),
+		UserID: user.ID,
+	}
+	if len(cmd.Args) == 1 {
+		feed, err := getFeed(s, cmd.Args[0])
+		if err != nil {
+			return err
+		}
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+
+	marked, err := s.DB.MarkAllPostsRead(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Marked %v posts as read\n", marked)
+	return nil
+}
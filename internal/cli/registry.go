@@ -0,0 +1,277 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+type Command struct {
+	Name		string
+	Args		[]string
+	// info is set by Commands.Run to the command being run
+	info		*CommandInfo
+	// values of the declared flags, parsed out of Args by Commands.Run
+	flags		map[string]any
+	given		map[string]bool
+}
+
+var ErrUnknownCommand = errors.New("unknown command")
+
+// ErrUsage is matched by every error caused by a command line not fitting the usage of its command
+var ErrUsage = errors.New("incorrect usage")
+
+// Many is the MaxArgs of commands taking any number of arguments
+const Many = -1
+
+// CommandInfo describes a command, help and argument checks are made from it
+type CommandInfo struct {
+	Name		string
+	Aliases		[]string
+	Group		string
+	Summary		string
+	// Usage shows the positional arguments coming after the command name
+	Usage		string
+	// MinArgs and MaxArgs bound the positional arguments, flags aren't counted
+	MinArgs		int
+	MaxArgs		int
+	Flags		[]Flag
+	Handler		func(*service.State, Command) error
+	// UserHandler is used instead of Handler by commands which need a logged in user
+	UserHandler	func(*service.State, Command, database.User) error
+}
+
+func (info *CommandInfo) LoginRequired() bool {
+	return info.UserHandler != nil
+}
+
+// UsageLine is the full usage of the command, like "gator follow <feed_url>"
+func (info *CommandInfo) UsageLine() string {
+	parts := []string{"gator", info.Name}
+	if info.Usage != "" {
+		parts = append(parts, info.Usage)
+	}
+	for _, f := range info.Flags {
+		parts = append(parts, "["+f.usage()+"]")
+	}
+	return strings.Join(parts, " ")
+}
+
+type usageError struct {
+	msg		string
+	usage	string
+}
+
+func (e *usageError) Error() string {
+	return fmt.Sprintf("%v\nUsage: %v", e.msg, e.usage)
+}
+
+func (e *usageError) Is(target error) bool {
+	return target == ErrUsage
+}
+
+// usageError reports a command line that doesn't fit the command, followed by its usage
+func (cmd Command) usageError(format string, a ...any) error {
+	usage := cmd.Name
+	if cmd.info != nil {
+		usage = cmd.info.UsageLine()
+	}
+	return &usageError{msg: fmt.Sprintf(format, a...), usage: usage}
+}
+
+type Commands struct {
+	knownCommands	map[string]*CommandInfo
+	aliases			map[string]string
+	// commands in the order they were registered, help lists them that way
+	ordered			[]*CommandInfo
+}
+
+// Register adds a command, a name or alias taken twice is a programming error
+func (c *Commands) Register(info CommandInfo) {
+	if c.knownCommands == nil {
+		c.knownCommands = make(map[string]*CommandInfo)
+		c.aliases = make(map[string]string)
+	}
+	if (info.Handler == nil) == (info.UserHandler == nil) {
+		panic(fmt.Sprintf("command %v needs exactly one of Handler and UserHandler", info.Name))
+	}
+
+	for _, f := range info.Flags {
+		// a broken default panics here instead of when the command is run
+		f.defaultValue()
+	}
+
+	for _, name := range append([]string{info.Name}, info.Aliases...) {
+		if _, ok := c.Lookup(name); ok {
+			panic(fmt.Sprintf("command %v is registered twice", name))
+		}
+	}
+
+	c.knownCommands[info.Name] = &info
+	for _, alias := range info.Aliases {
+		c.aliases[alias] = info.Name
+	}
+	c.ordered = append(c.ordered, &info)
+}
+
+// Lookup finds a command by its name or one of its aliases
+func (c *Commands) Lookup(name string) (*CommandInfo, bool) {
+	if original, ok := c.aliases[name]; ok {
+		name = original
+	}
+	info, ok := c.knownCommands[name]
+	return info, ok
+}
+
+func (c *Commands) Run(s *service.State, cmd Command) error {
+	info, ok := c.Lookup(cmd.Name)
+	if !ok {
+		return c.unknownCommand(cmd.Name)
+	}
+	cmd.Name = info.Name
+	cmd.info = info
+
+	if err := cmd.parseFlags(info.Flags); err != nil {
+		return err
+	}
+	if len(cmd.Args) < info.MinArgs || (info.MaxArgs != Many && len(cmd.Args) > info.MaxArgs) {
+		return cmd.usageError("incorrect amount of arguments in command call: <%v>", cmd.Name)
+	}
+
+	if info.LoginRequired() {
+		return MiddlewareLoggedIn(info.UserHandler)(s, cmd)
+	}
+	return info.Handler(s, cmd)
+}
+
+func (c *Commands) unknownCommand(name string) error {
+	hint := ""
+	if suggestions := c.suggest(name); len(suggestions) > 0 {
+		hint = fmt.Sprintf("\nDid you mean: %v?", strings.Join(suggestions, ", "))
+	}
+	return fmt.Errorf("%w: %v%v\nRun gator help to see all commands", ErrUnknownCommand, name, hint)
+}
+
+// maxSuggestions is how many commands a typo can be answered with
+const maxSuggestions = 3
+
+// suggest finds the commands name was probably meant to be: the ones it starts,
+// or the ones at most two edits away, closest first
+func (c *Commands) suggest(name string) []string {
+	distances := make(map[string]int)
+	consider := func(candidate, command string) {
+		distance := levenshtein(name, candidate)
+		if distance > 2 && !(len(name) >= 2 && strings.HasPrefix(candidate, name)) {
+			return
+		}
+		if previous, ok := distances[command]; !ok || distance < previous {
+			distances[command] = distance
+		}
+	}
+	for command := range c.knownCommands {
+		consider(command, command)
+	}
+	for alias, command := range c.aliases {
+		consider(alias, command)
+	}
+
+	suggestions := make([]string, 0, len(distances))
+	for command := range distances {
+		suggestions = append(suggestions, command)
+	}
+	sort.Slice(suggestions, func(i, j int) bool {
+		if distances[suggestions[i]] != distances[suggestions[j]] {
+			return distances[suggestions[i]] < distances[suggestions[j]]
+		}
+		return suggestions[i] < suggestions[j]
+	})
+	if len(suggestions) > maxSuggestions {
+		suggestions = suggestions[:maxSuggestions]
+	}
+	return suggestions
+}
+
+// levenshtein counts the single letter insertions, deletions and substitutions turning a into b
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur := make([]int, len(rb)+1)
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(rb)]
+}
+
+// handlerHelp lists every command by group, or describes the one it is given
+func (c *Commands) handlerHelp(s *service.State, cmd Command) error {
+	if len(cmd.Args) == 1 {
+		info, ok := c.Lookup(cmd.Args[0])
+		if !ok {
+			return c.unknownCommand(cmd.Args[0])
+		}
+
+		fmt.Printf("Usage: %v\n\n%v\n", info.UsageLine(), info.Summary)
+		if len(info.Flags) > 0 {
+			fmt.Printf("\nOptions:\n")
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, f := range info.Flags {
+				summary := f.Summary
+				if f.Default != "" {
+					summary += fmt.Sprintf(" (default %v)", f.Default)
+				}
+				fmt.Fprintf(w, "  %v\t%v\n", f.usage(), summary)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+			fmt.Println()
+		}
+		if len(info.Aliases) > 0 {
+			fmt.Printf("Aliases: %v\n", strings.Join(info.Aliases, ", "))
+		}
+		if info.LoginRequired() {
+			fmt.Printf("Needs a logged in user.\n")
+		}
+		return nil
+	}
+
+	fmt.Printf("Usage: gator [--ephemeral] <command> [arguments]\n")
+	fmt.Printf("Without a command gator starts the TUI.\n")
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	group := ""
+	for _, info := range c.ordered {
+		if info.Group != group {
+			group = info.Group
+			fmt.Fprintf(w, "\n%v:\n", group)
+		}
+		login := " "
+		if info.LoginRequired() {
+			login = "*"
+		}
+		fmt.Fprintf(w, "  %v %v\t%v\n", login, info.Name, info.Summary)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nCommands marked with * need a logged in user.\n")
+	fmt.Printf("Run gator help <command> to see its arguments.\n")
+	return nil
+}
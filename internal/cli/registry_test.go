@@ -0,0 +1,53 @@
+package cli
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b	string
+		want	int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "", b: "feeds", want: 5},
+		{a: "feeds", b: "", want: 5},
+		{a: "follow", b: "follow", want: 0},
+		{a: "folow", b: "follow", want: 1},
+		{a: "follwo", b: "follow", want: 2},
+		{a: "brwse", b: "browse", want: 1},
+		{a: "login", b: "logout", want: 3},
+		{a: "kitten", b: "sitting", want: 3},
+		{a: "naïve", b: "naive", want: 1},
+	}
+	for _, test := range tests {
+		if got := levenshtein(test.a, test.b); got != test.want {
+			t.Errorf("levenshtein(%q, %q) = %v, want %v", test.a, test.b, got, test.want)
+		}
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	c := NewCommands()
+	tests := []struct {
+		name	string
+		want	[]string
+	}{
+		{name: "brows", want: []string{"browse"}},
+		{name: "logn", want: []string{"login"}},
+		{name: "unfolow", want: []string{"unfollow"}},
+		// the closest come first, ties in name order
+		{name: "folow", want: []string{"follow", "following"}},
+		// no more than maxSuggestions
+		{name: "searh", want: []string{"search", "star", "starred"}},
+		// a start of two letters or more is enough, however far it is
+		{name: "mig", want: []string{"agg", "migrate"}},
+		{name: "xyzzy", want: []string{}},
+	}
+	for _, test := range tests {
+		if got := c.suggest(test.name); !slices.Equal(got, test.want) {
+			t.Errorf("suggest(%q) = %v, want %v", test.name, got, test.want)
+		}
+	}
+}
@@ -0,0 +1,189 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+)
+
+const retentionUsage = "[feed_url] [<keep_last|-> <max_age|-> [keep_unread yes|no|-]]"
+
+var retentionFlags = []Flag{
+	{Name: "global", Kind: FlagBool, Summary: "show or set the policy of feeds without their own, instead of a feed's one"},
+}
+
+// HandlerRetention shows or sets the retention policy of a feed or the global one,
+// "-" removes a limit (a feed then falls back to the global limit)
+func HandlerRetention(s *service.State, cmd Command, user database.User) error {
+	global := cmd.flagBool("global")
+	limits := cmd.Args
+
+	var feed database.Feed
+	if !global {
+		if len(limits) == 0 {
+			return cmd.usageError("either a feed_url or --global is needed")
+		}
+		var err error
+		feed, err = getManagedFeed(s, user, limits[0])
+		if err != nil {
+			return err
+		}
+		limits = limits[1:]
+	}
+	if len(limits) == 1 || len(limits) > 3 {
+		return cmd.usageError("incorrect amount of arguments in command call: <%v>", cmd.Name)
+	}
+
+	if len(limits) >= 2 {
+		keepLast, maxAge, err := parseRetentionArgs(limits[0], limits[1])
+		if err != nil {
+			return cmd.usageError("%v", err)
+		}
+
+		keepUnread := feed.RetentionKeepUnread
+		if global {
+			keepUnread = sql.NullBool{}
+			if s.Config.RetentionKeepUnread != nil {
+				keepUnread = sql.NullBool{Bool: *s.Config.RetentionKeepUnread, Valid: true}
+			}
+		}
+		if len(limits) == 3 {
+			switch limits[2] {
+			case "yes":
+				keepUnread = sql.NullBool{Bool: true, Valid: true}
+			case "no":
+				keepUnread = sql.NullBool{Bool: false, Valid: true}
+			case "-":
+				keepUnread = sql.NullBool{}
+			default:
+				return cmd.usageError("invalid keep_unread provided: %v", limits[2])
+			}
+		}
+
+		if global {
+			maxAgeStr := ""
+			if maxAge.Valid {
+				maxAgeStr = service.FormatRetentionAge(time.Duration(maxAge.Int64) * time.Second)
+			}
+			var keepUnreadSetting *bool
+			if keepUnread.Valid {
+				keepUnreadSetting = &keepUnread.Bool
+			}
+			if err := s.Config.SetRetention(keepLast.Int32, maxAgeStr, keepUnreadSetting); err != nil {
+				return err
+			}
+		} else {
+			feed, err = s.DB.SetFeedRetention(context.Background(), database.SetFeedRetentionParams{
+				ID: feed.ID,
+				RetentionKeepLast: keepLast,
+				RetentionMaxAgeSeconds: maxAge,
+				RetentionKeepUnread: keepUnread,
+				UpdatedAt: time.Now().UTC(),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	if global {
+		policy, err := service.GlobalRetention(s)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Global retention: %v\n", policy)
+		return nil
+	}
+
+	policy, err := service.FeedRetention(s, feed)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Retention of %v: %v\n", feed.Name, policy)
+	return nil
+}
+
+func parseRetentionArgs(keepLastArg, maxAgeArg string) (sql.NullInt32, sql.NullInt64, error) {
+	var keepLast sql.NullInt32
+	if keepLastArg != "-" {
+		n, err := strconv.Atoi(keepLastArg)
+		if err != nil || n <= 0 {
+			return sql.NullInt32{}, sql.NullInt64{}, fmt.Errorf("invalid keep_last provided: %v", keepLastArg)
+		}
+		keepLast = sql.NullInt32{Int32: int32(n), Valid: true}
+	}
+
+	var maxAge sql.NullInt64
+	if maxAgeArg != "-" {
+		d, err := service.ParseRetentionAge(maxAgeArg)
+		if err != nil || d == 0 {
+			return sql.NullInt32{}, sql.NullInt64{}, fmt.Errorf("invalid max_age provided: %v", maxAgeArg)
+		}
+		maxAge = sql.NullInt64{Int64: int64(d / time.Second), Valid: true}
+	}
+
+	return keepLast, maxAge, nil
+}
+
+var pruneFlags = []Flag{
+	{Name: "dry-run", Kind: FlagBool, Summary: "list the posts which would be removed without removing them"},
+}
+
+// HandlerPrune deletes the posts past retention of one feed or of every feed user can manage
+func HandlerPrune(s *service.State, cmd Command, user database.User) error {
+	dryRun := cmd.flagBool("dry-run")
+	var feedURL string
+	if len(cmd.Args) == 1 {
+		feedURL = cmd.Args[0]
+	}
+
+	var feeds []database.Feed
+	if feedURL != "" {
+		feed, err := getManagedFeed(s, user, feedURL)
+		if err != nil {
+			return err
+		}
+		feeds = append(feeds, feed)
+	} else {
+		all, err := s.DB.GetAllFeeds(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, feed := range all {
+			if service.CanManageFeed(user, feed) {
+				feeds = append(feeds, feed)
+			}
+		}
+	}
+
+	total := 0
+	for _, feed := range feeds {
+		posts, err := service.PruneFeed(s, feed, dryRun)
+		if err != nil {
+			return err
+		}
+		if len(posts) == 0 {
+			continue
+		}
+
+		total += len(posts)
+		fmt.Printf("* %v: %v posts\n", feed.Name, len(posts))
+		if dryRun {
+			for _, post := range posts {
+				fmt.Printf("\t- %v (%v)\n", post.Title, post.PublishedAt.Format(time.DateOnly))
+			}
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("%v posts would be removed\n", total)
+	} else {
+		fmt.Printf("%v posts were removed\n", total)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/servicetest"
+)
+
+func TestRetentionAndPruneNeedTheFeedOwner(t *testing.T) {
+	s := servicetest.NewState(t)
+	mustRun(t, s, "register", "alice")
+	mustRun(t, s, "addfeed", "blog", "http://example.com/feed")
+	feed, err := s.DB.GetFeedByURL(context.Background(), "http://example.com/feed")
+	if err != nil {
+		t.Fatalf("GetFeedByURL: %v", err)
+	}
+	servicetest.CreatePosts(t, s, feed, 3)
+	mustRun(t, s, "retention", "http://example.com/feed", "1", "-", "no")
+
+	mustRun(t, s, "register", "bob")
+	if _, err := run(t, s, "retention", "http://example.com/feed", "-", "-"); !errors.Is(err, service.ErrNotFeedOwner) {
+		t.Errorf("bob set the retention of alice's feed: %v", err)
+	}
+	if _, err := run(t, s, "prune", "http://example.com/feed"); !errors.Is(err, service.ErrNotFeedOwner) {
+		t.Errorf("bob pruned alice's feed: %v", err)
+	}
+	if out := mustRun(t, s, "prune"); !strings.Contains(out, "0 posts were removed") {
+		t.Errorf("bob's prune reached a feed bob doesn't manage:\n%v", out)
+	}
+
+	mustRun(t, s, "login", "alice")
+	if out := mustRun(t, s, "prune"); !strings.Contains(out, "2 posts were removed") {
+		t.Errorf("alice's prune of the feed alice owns:\n%v", out)
+	}
+}
@@ -0,0 +1,203 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func HandlerStar(s *service.State, cmd Command, user database.User) error {
+	post, err := findPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	err = s.DB.StarPost(context.Background(), database.StarPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		StarredAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Starred: %v\n", post.Title)
+	return nil
+}
+
+func HandlerUnstar(s *service.State, cmd Command, user database.User) error {
+	post, err := findPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	removed, err := s.DB.UnstarPost(context.Background(), database.UnstarPostParams{
+		UserID: user.ID,
+		PostID: post.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if removed == 0 {
+		return fmt.Errorf("post isn't starred: %v", post.Title)
+	}
+
+	fmt.Printf("Unstarred: %v\n", post.Title)
+	return nil
+}
+
+func HandlerStarred(s *service.State, cmd Command, user database.User) error {
+	posts, err := s.DB.GetStarredPostsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 {
+		fmt.Printf("You don't have any starred posts yet!\n")
+		return nil
+	}
+
+	fmt.Printf("Posts starred by %v:\n", user.Name)
+	for _, post := range posts {
+		fmt.Printf("\t* %v\n\t  %v\n", post.Title, post.Url)
+	}
+
+	return nil
+}
+
+var laterFlags = []Flag{
+	{Name: "remove", Kind: FlagBool, Summary: "take the post out of the queue"},
+}
+
+// HandlerLater puts a post at the end of the read later queue, or takes it out with --remove
+func HandlerLater(s *service.State, cmd Command, user database.User) error {
+	remove := cmd.flagBool("remove")
+
+	post, err := findPost(s, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	if remove {
+		removed, err := s.DB.RemoveFromReadLater(context.Background(), database.RemoveFromReadLaterParams{
+			UserID: user.ID,
+			PostID: post.ID,
+		})
+		if err != nil {
+			return err
+		}
+		if removed == 0 {
+			return fmt.Errorf("post isn't in your queue: %v", post.Title)
+		}
+
+		fmt.Printf("Removed from queue: %v\n", post.Title)
+		return nil
+	}
+
+	err = s.DB.AddToReadLater(context.Background(), database.AddToReadLaterParams{
+		UserID: user.ID,
+		PostID: post.ID,
+		AddedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Added to queue: %v\n", post.Title)
+	return nil
+}
+
+func HandlerQueue(s *service.State, cmd Command, user database.User) error {
+	posts, err := s.DB.GetReadLaterForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+
+	if len(posts) == 0 {
+		fmt.Printf("Your read later queue is empty!\n")
+		return nil
+	}
+
+	fmt.Printf("Read later queue of %v:\n", user.Name)
+	for i, post := range posts {
+		fmt.Printf("\t%v. %v\n\t   %v\n", i+1, post.Title, post.Url)
+	}
+
+	return nil
+}
+
+type savedPost struct {
+	Title		string		`json:"title"`
+	URL			string		`json:"url"`
+	PublishedAt	time.Time	`json:"published_at"`
+	SavedAt		time.Time	`json:"saved_at"`
+}
+
+type savedExport struct {
+	Starred	[]savedPost	`json:"starred"`
+	Queue	[]savedPost	`json:"queue"`
+}
+
+func savedPosts(s *service.State, user database.User) (savedExport, error) {
+	export := savedExport{
+		Starred: []savedPost{},
+		Queue: []savedPost{},
+	}
+
+	starred, err := s.DB.GetStarredPostsForUser(context.Background(), user.ID)
+	if err != nil {
+		return savedExport{}, err
+	}
+	for _, post := range starred {
+		export.Starred = append(export.Starred, savedPost{
+			Title: post.Title,
+			URL: post.Url,
+			PublishedAt: post.PublishedAt,
+			SavedAt: post.StarredAt,
+		})
+	}
+
+	queue, err := s.DB.GetReadLaterForUser(context.Background(), user.ID)
+	if err != nil {
+		return savedExport{}, err
+	}
+	for _, post := range queue {
+		export.Queue = append(export.Queue, savedPost{
+			Title: post.Title,
+			URL: post.Url,
+			PublishedAt: post.PublishedAt,
+			SavedAt: post.AddedAt,
+		})
+	}
+
+	return export, nil
+}
+
+// HandlerExportSaved writes starred posts and the read later queue as JSON to a file or stdout
+func HandlerExportSaved(s *service.State, cmd Command, user database.User) error {
+	export, err := savedPosts(s, user)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Args) == 0 {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(cmd.Args[0], data, 0o644); err != nil {
+		return err
+	}
+	fmt.Printf("Saved posts were exported to %v\n", cmd.Args[0])
+	return nil
+}
@@ -0,0 +1,83 @@
+package cli
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+var searchFlags = []Flag{
+	{Name: "all", Kind: FlagBool, Summary: "search every feed, not only followed ones"},
+	{Name: "folder", Kind: FlagString, Value: "name", Summary: "only search feeds of a folder"},
+	{Name: "since", Kind: FlagDate, Summary: "only find posts published on or after the day"},
+	{Name: "until", Kind: FlagDate, Summary: "only find posts published on or before the day"},
+	{Name: "limit", Kind: FlagInt, Value: "n", Default: "10", Summary: "how many posts to show"},
+}
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+func HandlerSearch(s *service.State, cmd Command, user database.User) error {
+	loc := service.Location(user)
+	params := database.SearchPostsParams{
+		UserID: uuid.NullUUID{UUID: user.ID, Valid: true},
+		Limit: int32(cmd.flagInt("limit")),
+	}
+	if cmd.flagBool("all") {
+		params.UserID = uuid.NullUUID{}
+	}
+	if params.Limit < 1 {
+		return fmt.Errorf("invalid limit provided: %v", params.Limit)
+	}
+
+	if name := cmd.flagString("folder"); name != "" {
+		folder, err := getFolder(s, user, name)
+		if err != nil {
+			return err
+		}
+		params.FolderID = uuid.NullUUID{UUID: folder.ID, Valid: true}
+	}
+	if since, ok := cmd.flagDate("since", loc); ok {
+		params.Since = sql.NullTime{Time: since, Valid: true}
+	}
+	if until, ok := cmd.flagDate("until", loc); ok {
+		// the whole until day is included
+		params.Until = sql.NullTime{Time: until.AddDate(0, 0, 1), Valid: true}
+	}
+
+	query, err := service.ParseSearchQuery(strings.Join(cmd.Args, " "))
+	if err != nil {
+		return err
+	}
+	params.Query = query
+
+	results, err := s.DB.SearchPosts(context.Background(), params)
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("Nothing was found\n")
+		return nil
+	}
+
+	fmt.Printf("Found %v posts:\n", len(results))
+	for _, result := range results {
+		fmt.Printf("--- %s ---\n", result.TitleHeadline)
+		fmt.Printf("    %v, %v\n", result.FeedName, result.PublishedAt.In(loc).Format(time.DateOnly))
+		if snippet := strings.Join(strings.Fields(htmlTag.ReplaceAllString(result.Snippet, " ")), " "); snippet != "" {
+			fmt.Printf("    %v\n", snippet)
+		}
+		fmt.Printf("    Link: %s\n", result.Url)
+		fmt.Println("=====================================")
+	}
+
+	return nil
+}
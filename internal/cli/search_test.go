@@ -0,0 +1,19 @@
+package cli
+
+import (
+	"strings"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/servicetest"
+)
+
+func TestSearchFindsPostsSightedInFollowedFeeds(t *testing.T) {
+	s := servicetest.NewState(t)
+	seedSighting(t, s)
+
+	// bob only follows the mirror, which carries Post 1 but not Post 0
+	out := mustRun(t, s, "search", "post")
+	if !strings.Contains(out, "Found 1 posts:") || !strings.Contains(out, "Link: http://example.com/1\n") {
+		t.Errorf("search through a followed mirror:\n%v", out)
+	}
+}
@@ -0,0 +1,224 @@
+package cli
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func HandlerRenameUser(s *service.State, cmd Command, user database.User) error {
+	checkDup, err := s.DB.GetUserByName(context.Background(), cmd.Args[0])
+	if err == nil && checkDup.ID != user.ID {
+		return fmt.Errorf("user already exists: %v", cmd.Args[0])
+	}
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	renamed, err := s.DB.RenameUser(context.Background(), database.RenameUserParams{
+		ID: user.ID,
+		Name: cmd.Args[0],
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		return err
+	}
+
+	// the config points at the user by name, so it has to follow the rename
+	if err := s.Config.SetUser(renamed.Name); err != nil {
+		return err
+	}
+
+	fmt.Printf("User was renamed: %v -> %v\n", user.Name, renamed.Name)
+	return nil
+}
+
+// HandlerTimezone shows or sets the timezone dates are shown to the user in
+func HandlerTimezone(s *service.State, cmd Command, user database.User) error {
+	if len(cmd.Args) == 0 {
+		fmt.Printf("Dates are shown in: %v\n", timezoneName(user))
+		return nil
+	}
+
+	updated, err := service.SetTimezone(s, user, cmd.Args[0])
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("Dates are now shown in: %v\n", timezoneName(updated))
+	return nil
+}
+
+func timezoneName(user database.User) string {
+	if user.Timezone == "" {
+		zone, _ := time.Now().Zone()
+		return fmt.Sprintf("%v (%v)", service.LocalTimezone, zone)
+	}
+	return user.Timezone
+}
+
+var deleteUserFlags = []Flag{
+	{Name: "yes", Kind: FlagBool, Summary: "don't ask to type the name again"},
+}
+
+// HandlerDeleteUser deletes the current user, admins may delete anyone.
+// Without --yes the name has to be typed once more to confirm.
+func HandlerDeleteUser(s *service.State, cmd Command, user database.User) error {
+	var name string
+	if len(cmd.Args) == 1 {
+		name = cmd.Args[0]
+	}
+	confirmed := cmd.flagBool("yes")
+
+	target := user
+	if name != "" && name != user.Name {
+		if !user.IsAdmin {
+			return fmt.Errorf("only admins can delete other users")
+		}
+
+		var err error
+		target, err = s.DB.GetUserByName(context.Background(), name)
+		if err != nil {
+			return fmt.Errorf("user don't exist")
+		}
+	}
+
+	if !confirmed {
+		fmt.Printf("This deletes %v with all their follows, folders, hooks and saved posts.\n", target.Name)
+		fmt.Printf("Type the user name to confirm: ")
+
+		answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && answer == "" {
+			return fmt.Errorf("user wasn't deleted")
+		}
+		if strings.TrimSpace(answer) != target.Name {
+			return fmt.Errorf("names don't match, user wasn't deleted")
+		}
+	}
+
+	handedOff, removedFeeds, err := service.DeleteUser(s, target)
+	if err != nil {
+		return err
+	}
+
+	if target.Name == s.Config.CurrentUsername {
+		if err := s.Config.SetUser(""); err != nil {
+			return err
+		}
+	}
+
+	fmt.Printf("User was deleted: %v\n", target.Name)
+	if handedOff > 0 || removedFeeds > 0 {
+		fmt.Printf("%v owned feeds were handed off, %v feeds without followers were removed\n", handedOff, removedFeeds)
+	}
+	return nil
+}
+
+type exportProfile struct {
+	Name		string		`json:"name"`
+	IsAdmin		bool		`json:"is_admin"`
+	Timezone	string		`json:"timezone,omitempty"`
+	CreatedAt	time.Time	`json:"created_at"`
+}
+
+type exportFollow struct {
+	FeedName	string		`json:"feed_name"`
+	FeedURL		string		`json:"feed_url"`
+	DisplayName	string		`json:"display_name,omitempty"`
+	Notes		string		`json:"notes,omitempty"`
+	Folders		[]string	`json:"folders"`
+	FollowedAt	time.Time	`json:"followed_at"`
+}
+
+type exportRead struct {
+	Title	string		`json:"title"`
+	URL		string		`json:"url"`
+	ReadAt	time.Time	`json:"read_at"`
+}
+
+type userExport struct {
+	Profile	exportProfile	`json:"profile"`
+	Follows	[]exportFollow	`json:"follows"`
+	Read	[]exportRead	`json:"read"`
+	savedExport
+}
+
+// HandlerExportMe writes everything gator keeps about the current user as JSON to a file or stdout
+func HandlerExportMe(s *service.State, cmd Command, user database.User) error {
+	export := userExport{
+		Profile: exportProfile{
+			Name: user.Name,
+			IsAdmin: user.IsAdmin,
+			Timezone: user.Timezone,
+			CreatedAt: user.CreatedAt,
+		},
+		Follows: []exportFollow{},
+		Read: []exportRead{},
+	}
+
+	follows, err := s.DB.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	folders, err := s.DB.GetFollowFoldersForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	for _, follow := range follows {
+		item := exportFollow{
+			FeedName: follow.OriginalName,
+			FeedURL: follow.FeedUrl,
+			DisplayName: follow.DisplayName.String,
+			Notes: follow.Notes.String,
+			Folders: []string{},
+			FollowedAt: follow.CreatedAt,
+		}
+		for _, folder := range folders {
+			if folder.FeedFollowID == follow.ID {
+				item.Folders = append(item.Folders, folder.FolderName)
+			}
+		}
+		export.Follows = append(export.Follows, item)
+	}
+
+	reads, err := s.DB.GetReadPostsForUser(context.Background(), user.ID)
+	if err != nil {
+		return err
+	}
+	for _, post := range reads {
+		export.Read = append(export.Read, exportRead{
+			Title: post.Title,
+			URL: post.Url,
+			ReadAt: post.ReadAt,
+		})
+	}
+
+	export.savedExport, err = savedPosts(s, user)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(export, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	if len(cmd.Args) == 0 {
+		fmt.Println(string(data))
+		return nil
+	}
+
+	if err := os.WriteFile(cmd.Args[0], data, 0o600); err != nil {
+		return err
+	}
+	fmt.Printf("Your data was exported to %v\n", cmd.Args[0])
+	return nil
+}
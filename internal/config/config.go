@@ -7,8 +7,23 @@ import (
 )
 
 type Config struct {
-	DBUrl			string	`json:"db_url"`
-	CurrentUsername	string	`json:"current_user_name"`
+	DBUrl				string	`json:"db_url"`
+	CurrentUsername		string	`json:"current_user_name"`
+	RetentionKeepLast	int32	`json:"retention_keep_last,omitempty"`
+	RetentionMaxAge		string	`json:"retention_max_age,omitempty"`
+	// RetentionKeepUnread is read through KeepUnread, unread posts are kept unless it is false
+	RetentionKeepUnread	*bool	`json:"retention_keep_unread,omitempty"`
+	LogLevel			string	`json:"log_level,omitempty"`
+	LogFormat			string	`json:"log_format,omitempty"`
+	LogFile				string	`json:"log_file,omitempty"`
+	UserAgent			string	`json:"user_agent,omitempty"`
+	ContactURL			string	`json:"contact_url,omitempty"`
+	HostInterval		string	`json:"host_interval,omitempty"`
+	MaxPerHost			int		`json:"max_per_host,omitempty"`
+	CheckRobots			bool	`json:"check_robots,omitempty"`
+	AllowCommandHooks	bool	`json:"allow_command_hooks,omitempty"`
+	// Ephemeral keeps changes in memory, the file on disk stays as it was
+	Ephemeral			bool	`json:"-"`
 }
 
 const configFileName = ".gatorconfig.json"
@@ -57,6 +72,25 @@ func write(cfg Config) error {
 
 func (c *Config) SetUser(username string) (error) {
 	c.CurrentUsername = username
-	return write(*c)
+	return c.save()
+}
+
+// KeepUnread tells whether pruning spares unread posts, it does unless the config says otherwise
+func (c *Config) KeepUnread() bool {
+	return c.RetentionKeepUnread == nil || *c.RetentionKeepUnread
+}
+
+// SetRetention stores the global retention policy, a nil keepUnread goes back to the default
+func (c *Config) SetRetention(keepLast int32, maxAge string, keepUnread *bool) error {
+	c.RetentionKeepLast = keepLast
+	c.RetentionMaxAge = maxAge
+	c.RetentionKeepUnread = keepUnread
+	return c.save()
 }
 
+func (c *Config) save() error {
+	if c.Ephemeral {
+		return nil
+	}
+	return write(*c)
+}
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"time"
 
 	"github.com/google/uuid"
@@ -16,10 +17,10 @@ const createFeedFollow = `-- name: CreateFeedFollow :one
 WITH inserted_feed_follow AS (
     INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
     VALUES ($1, $2, $3, $4, $5)
-    RETURNING id, created_at, updated_at, user_id, feed_id
+    RETURNING id, created_at, updated_at, user_id, feed_id, display_name, notes
 )
 SELECT
-    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id,
+    inserted_feed_follow.id, inserted_feed_follow.created_at, inserted_feed_follow.updated_at, inserted_feed_follow.user_id, inserted_feed_follow.feed_id, inserted_feed_follow.display_name, inserted_feed_follow.notes,
     feeds.name AS feed_name,
     users.name AS user_name
 FROM inserted_feed_follow
@@ -36,13 +37,15 @@ type CreateFeedFollowParams struct {
 }
 
 type CreateFeedFollowRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  string
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.UUID
+	DisplayName sql.NullString
+	Notes       sql.NullString
+	FeedName    string
+	UserName    string
 }
 
 func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowParams) (CreateFeedFollowRow, error) {
@@ -60,6 +63,8 @@ func (q *Queries) CreateFeedFollow(ctx context.Context, arg CreateFeedFollowPara
 		&i.UpdatedAt,
 		&i.UserID,
 		&i.FeedID,
+		&i.DisplayName,
+		&i.Notes,
 		&i.FeedName,
 		&i.UserName,
 	)
@@ -94,9 +99,45 @@ func (q *Queries) DeleteFeedFollows(ctx context.Context) error {
 	return err
 }
 
+const getFeedFollowByURL = `-- name: GetFeedFollowByURL :one
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.display_name, feed_follows.notes FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1 AND feeds.url = $2
+LIMIT 1
+`
+
+type GetFeedFollowByURLParams struct {
+	UserID uuid.UUID
+	Url    string
+}
+
+func (q *Queries) GetFeedFollowByURL(ctx context.Context, arg GetFeedFollowByURLParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, getFeedFollowByURL, arg.UserID, arg.Url)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.DisplayName,
+		&i.Notes,
+	)
+	return i, err
+}
+
 const getFeedFollowsForUser = `-- name: GetFeedFollowsForUser :many
 
-SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feeds.name AS feed_name, users.name AS user_name
+SELECT feed_follows.id, feed_follows.created_at, feed_follows.updated_at, feed_follows.user_id, feed_follows.feed_id, feed_follows.display_name, feed_follows.notes, COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name, feeds.name AS original_name, feeds.url AS feed_url, users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM feed_posts
+        WHERE feed_posts.feed_id = feed_follows.feed_id
+        AND NOT EXISTS (
+            SELECT 1 FROM post_reads
+            WHERE post_reads.post_id = feed_posts.post_id
+            AND post_reads.user_id = feed_follows.user_id
+        )
+    ) AS unread_count
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
@@ -104,15 +145,21 @@ WHERE feed_follows.user_id = $1
 `
 
 type GetFeedFollowsForUserRow struct {
-	ID        uuid.UUID
-	CreatedAt time.Time
-	UpdatedAt time.Time
-	UserID    uuid.UUID
-	FeedID    uuid.UUID
-	FeedName  string
-	UserName  string
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	UserID       uuid.UUID
+	FeedID       uuid.UUID
+	DisplayName  sql.NullString
+	Notes        sql.NullString
+	FeedName     string
+	OriginalName string
+	FeedUrl      string
+	UserName     string
+	UnreadCount  int64
 }
 
+// feed_name is the user's own name for the feed when they gave it one
 func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]GetFeedFollowsForUserRow, error) {
 	rows, err := q.db.QueryContext(ctx, getFeedFollowsForUser, userID)
 	if err != nil {
@@ -128,8 +175,13 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 			&i.UpdatedAt,
 			&i.UserID,
 			&i.FeedID,
+			&i.DisplayName,
+			&i.Notes,
 			&i.FeedName,
+			&i.OriginalName,
+			&i.FeedUrl,
 			&i.UserName,
+			&i.UnreadCount,
 		); err != nil {
 			return nil, err
 		}
@@ -143,3 +195,61 @@ func (q *Queries) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) (
 	}
 	return items, nil
 }
+
+const setFeedFollowName = `-- name: SetFeedFollowName :one
+UPDATE feed_follows
+SET display_name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, user_id, feed_id, display_name, notes
+`
+
+type SetFeedFollowNameParams struct {
+	ID          uuid.UUID
+	DisplayName sql.NullString
+	UpdatedAt   time.Time
+}
+
+func (q *Queries) SetFeedFollowName(ctx context.Context, arg SetFeedFollowNameParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, setFeedFollowName, arg.ID, arg.DisplayName, arg.UpdatedAt)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.DisplayName,
+		&i.Notes,
+	)
+	return i, err
+}
+
+const setFeedFollowNotes = `-- name: SetFeedFollowNotes :one
+UPDATE feed_follows
+SET notes = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, user_id, feed_id, display_name, notes
+`
+
+type SetFeedFollowNotesParams struct {
+	ID        uuid.UUID
+	Notes     sql.NullString
+	UpdatedAt time.Time
+}
+
+func (q *Queries) SetFeedFollowNotes(ctx context.Context, arg SetFeedFollowNotesParams) (FeedFollow, error) {
+	row := q.db.QueryRowContext(ctx, setFeedFollowNotes, arg.ID, arg.Notes, arg.UpdatedAt)
+	var i FeedFollow
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.DisplayName,
+		&i.Notes,
+	)
+	return i, err
+}
@@ -11,6 +11,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createFeed = `-- name: CreateFeed :one
@@ -23,7 +24,7 @@ VALUES (
     $5,
     $6
 )
-RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
 `
 
 type CreateFeedParams struct {
@@ -32,7 +33,7 @@ type CreateFeedParams struct {
 	UpdatedAt time.Time
 	Name      string
 	Url       string
-	UserID    uuid.UUID
+	UserID    uuid.NullUUID
 }
 
 func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, error) {
@@ -53,10 +54,23 @@ func (q *Queries) CreateFeed(ctx context.Context, arg CreateFeedParams) (Feed, e
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
 	)
 	return i, err
 }
 
+const deleteFeed = `-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFeed, id)
+	return err
+}
+
 const deleteFeeds = `-- name: DeleteFeeds :exec
 DELETE FROM feeds
 `
@@ -66,8 +80,26 @@ func (q *Queries) DeleteFeeds(ctx context.Context) error {
 	return err
 }
 
+const deleteOrphanedFeeds = `-- name: DeleteOrphanedFeeds :execrows
+DELETE FROM feeds
+WHERE id = ANY($1::uuid[])
+AND user_id IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = feeds.id
+)
+`
+
+// only the given feeds are looked at, those without an owner and followers are deleted
+func (q *Queries) DeleteOrphanedFeeds(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteOrphanedFeeds, pq.Array(ids))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getAllFeeds = `-- name: GetAllFeeds :many
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread FROM feeds
 `
 
 func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
@@ -87,6 +119,9 @@ func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
 			&i.Name,
 			&i.Url,
 			&i.UserID,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeSeconds,
+			&i.RetentionKeepUnread,
 		); err != nil {
 			return nil, err
 		}
@@ -102,7 +137,7 @@ func (q *Queries) GetAllFeeds(ctx context.Context) ([]Feed, error) {
 }
 
 const getFeedByURL = `-- name: GetFeedByURL :one
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread FROM feeds
 WHERE url = $1 LIMIT 1
 `
 
@@ -117,12 +152,56 @@ func (q *Queries) GetFeedByURL(ctx context.Context, url string) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
 	)
 	return i, err
 }
 
+const getFeedsForUser = `-- name: GetFeedsForUser :many
+SELECT feeds.id, feeds.created_at, feeds.updated_at, feeds.last_fetched_at, feeds.name, feeds.url, feeds.user_id, feeds.retention_keep_last, feeds.retention_max_age_seconds, feeds.retention_keep_unread FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.name
+`
+
+func (q *Queries) GetFeedsForUser(ctx context.Context, userID uuid.UUID) ([]Feed, error) {
+	rows, err := q.db.QueryContext(ctx, getFeedsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Feed
+	for rows.Next() {
+		var i Feed
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.LastFetchedAt,
+			&i.Name,
+			&i.Url,
+			&i.UserID,
+			&i.RetentionKeepLast,
+			&i.RetentionMaxAgeSeconds,
+			&i.RetentionKeepUnread,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getNextFeedToFetch = `-- name: GetNextFeedToFetch :one
-SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id FROM feeds
+SELECT id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread FROM feeds
 ORDER BY last_fetched_at ASC NULLS FIRST
 LIMIT 1
 `
@@ -138,16 +217,75 @@ func (q *Queries) GetNextFeedToFetch(ctx context.Context) (Feed, error) {
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
 	)
 	return i, err
 }
 
+const getOwnedFeedIDs = `-- name: GetOwnedFeedIDs :many
+SELECT id FROM feeds
+WHERE user_id = $1
+`
+
+func (q *Queries) GetOwnedFeedIDs(ctx context.Context, userID uuid.NullUUID) ([]uuid.UUID, error) {
+	rows, err := q.db.QueryContext(ctx, getOwnedFeedIDs, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []uuid.UUID
+	for rows.Next() {
+		var id uuid.UUID
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const handOffOwnedFeeds = `-- name: HandOffOwnedFeeds :execrows
+UPDATE feeds
+SET user_id = (
+        SELECT feed_follows.user_id FROM feed_follows
+        WHERE feed_follows.feed_id = feeds.id
+        AND feed_follows.user_id <> $1
+        ORDER BY feed_follows.created_at
+        LIMIT 1
+    ),
+    updated_at = $2
+WHERE feeds.user_id = $1
+`
+
+type HandOffOwnedFeedsParams struct {
+	UserID    uuid.UUID
+	UpdatedAt time.Time
+}
+
+// every feed of the user goes to its longest standing other follower,
+// feeds nobody else follows are left without an owner
+func (q *Queries) HandOffOwnedFeeds(ctx context.Context, arg HandOffOwnedFeedsParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, handOffOwnedFeeds, arg.UserID, arg.UpdatedAt)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const markFeedFetched = `-- name: MarkFeedFetched :one
 UPDATE feeds
 SET last_fetched_at = $1,
     updated_at = $2
 WHERE id = $3
-RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
 `
 
 type MarkFeedFetchedParams struct {
@@ -167,6 +305,147 @@ func (q *Queries) MarkFeedFetched(ctx context.Context, arg MarkFeedFetchedParams
 		&i.Name,
 		&i.Url,
 		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
+	)
+	return i, err
+}
+
+const renameFeed = `-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
+`
+
+type RenameFeedParams struct {
+	ID        uuid.UUID
+	Name      string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) RenameFeed(ctx context.Context, arg RenameFeedParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, renameFeed, arg.ID, arg.Name, arg.UpdatedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
+	)
+	return i, err
+}
+
+const setFeedOwner = `-- name: SetFeedOwner :one
+UPDATE feeds
+SET user_id = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
+`
+
+type SetFeedOwnerParams struct {
+	ID        uuid.UUID
+	UserID    uuid.NullUUID
+	UpdatedAt time.Time
+}
+
+func (q *Queries) SetFeedOwner(ctx context.Context, arg SetFeedOwnerParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, setFeedOwner, arg.ID, arg.UserID, arg.UpdatedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
+	)
+	return i, err
+}
+
+const setFeedRetention = `-- name: SetFeedRetention :one
+UPDATE feeds
+SET retention_keep_last = $2,
+    retention_max_age_seconds = $3,
+    retention_keep_unread = $4,
+    updated_at = $5
+WHERE id = $1
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
+`
+
+type SetFeedRetentionParams struct {
+	ID                     uuid.UUID
+	RetentionKeepLast      sql.NullInt32
+	RetentionMaxAgeSeconds sql.NullInt64
+	RetentionKeepUnread    sql.NullBool
+	UpdatedAt              time.Time
+}
+
+func (q *Queries) SetFeedRetention(ctx context.Context, arg SetFeedRetentionParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, setFeedRetention,
+		arg.ID,
+		arg.RetentionKeepLast,
+		arg.RetentionMaxAgeSeconds,
+		arg.RetentionKeepUnread,
+		arg.UpdatedAt,
+	)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
+	)
+	return i, err
+}
+
+const setFeedURL = `-- name: SetFeedURL :one
+UPDATE feeds
+SET url = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, last_fetched_at, name, url, user_id, retention_keep_last, retention_max_age_seconds, retention_keep_unread
+`
+
+type SetFeedURLParams struct {
+	ID        uuid.UUID
+	Url       string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) SetFeedURL(ctx context.Context, arg SetFeedURLParams) (Feed, error) {
+	row := q.db.QueryRowContext(ctx, setFeedURL, arg.ID, arg.Url, arg.UpdatedAt)
+	var i Feed
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.LastFetchedAt,
+		&i.Name,
+		&i.Url,
+		&i.UserID,
+		&i.RetentionKeepLast,
+		&i.RetentionMaxAgeSeconds,
+		&i.RetentionKeepUnread,
 	)
 	return i, err
 }
@@ -0,0 +1,228 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: folders.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addFollowToFolder = `-- name: AddFollowToFolder :exec
+INSERT INTO feed_follow_folders (feed_follow_id, folder_id)
+VALUES ($1, $2)
+ON CONFLICT (feed_follow_id, folder_id) DO NOTHING
+`
+
+type AddFollowToFolderParams struct {
+	FeedFollowID uuid.UUID
+	FolderID     uuid.UUID
+}
+
+func (q *Queries) AddFollowToFolder(ctx context.Context, arg AddFollowToFolderParams) error {
+	_, err := q.db.ExecContext(ctx, addFollowToFolder, arg.FeedFollowID, arg.FolderID)
+	return err
+}
+
+const createFolder = `-- name: CreateFolder :one
+INSERT INTO folders (id, created_at, updated_at, user_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING id, created_at, updated_at, user_id, name
+`
+
+type CreateFolderParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	UserID    uuid.UUID
+	Name      string
+}
+
+func (q *Queries) CreateFolder(ctx context.Context, arg CreateFolderParams) (Folder, error) {
+	row := q.db.QueryRowContext(ctx, createFolder,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.Name,
+	)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const deleteFolder = `-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = $1
+`
+
+func (q *Queries) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deleteFolder, id)
+	return err
+}
+
+const getFolderByName = `-- name: GetFolderByName :one
+SELECT id, created_at, updated_at, user_id, name FROM folders
+WHERE user_id = $1 AND name = $2 LIMIT 1
+`
+
+type GetFolderByNameParams struct {
+	UserID uuid.UUID
+	Name   string
+}
+
+func (q *Queries) GetFolderByName(ctx context.Context, arg GetFolderByNameParams) (Folder, error) {
+	row := q.db.QueryRowContext(ctx, getFolderByName, arg.UserID, arg.Name)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
+
+const getFoldersForUser = `-- name: GetFoldersForUser :many
+SELECT id, created_at, updated_at, user_id, name FROM folders
+WHERE user_id = $1
+ORDER BY name
+`
+
+func (q *Queries) GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]Folder, error) {
+	rows, err := q.db.QueryContext(ctx, getFoldersForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Folder
+	for rows.Next() {
+		var i Folder
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.Name,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getFollowFoldersForUser = `-- name: GetFollowFoldersForUser :many
+SELECT feed_follow_folders.feed_follow_id, folders.id AS folder_id, folders.name AS folder_name
+FROM feed_follow_folders
+INNER JOIN folders ON feed_follow_folders.folder_id = folders.id
+WHERE folders.user_id = $1
+ORDER BY folders.name
+`
+
+type GetFollowFoldersForUserRow struct {
+	FeedFollowID uuid.UUID
+	FolderID     uuid.UUID
+	FolderName   string
+}
+
+func (q *Queries) GetFollowFoldersForUser(ctx context.Context, userID uuid.UUID) ([]GetFollowFoldersForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getFollowFoldersForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetFollowFoldersForUserRow
+	for rows.Next() {
+		var i GetFollowFoldersForUserRow
+		if err := rows.Scan(&i.FeedFollowID, &i.FolderID, &i.FolderName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeFollowFromAllFolders = `-- name: RemoveFollowFromAllFolders :exec
+DELETE FROM feed_follow_folders
+WHERE feed_follow_id = $1
+`
+
+func (q *Queries) RemoveFollowFromAllFolders(ctx context.Context, feedFollowID uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, removeFollowFromAllFolders, feedFollowID)
+	return err
+}
+
+const removeFollowFromFolder = `-- name: RemoveFollowFromFolder :execrows
+DELETE FROM feed_follow_folders
+WHERE feed_follow_id = $1 AND folder_id = $2
+`
+
+type RemoveFollowFromFolderParams struct {
+	FeedFollowID uuid.UUID
+	FolderID     uuid.UUID
+}
+
+func (q *Queries) RemoveFollowFromFolder(ctx context.Context, arg RemoveFollowFromFolderParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeFollowFromFolder, arg.FeedFollowID, arg.FolderID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const renameFolder = `-- name: RenameFolder :one
+UPDATE folders
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING id, created_at, updated_at, user_id, name
+`
+
+type RenameFolderParams struct {
+	ID        uuid.UUID
+	Name      string
+	UpdatedAt time.Time
+}
+
+func (q *Queries) RenameFolder(ctx context.Context, arg RenameFolderParams) (Folder, error) {
+	row := q.db.QueryRowContext(ctx, renameFolder, arg.ID, arg.Name, arg.UpdatedAt)
+	var i Folder
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.Name,
+	)
+	return i, err
+}
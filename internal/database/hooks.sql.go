@@ -0,0 +1,295 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: hooks.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createHook = `-- name: CreateHook :one
+INSERT INTO hooks (id, created_at, updated_at, user_id, feed_id, kind, target, template, max_attempts)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING id, created_at, updated_at, user_id, feed_id, kind, target, template, max_attempts
+`
+
+type CreateHookParams struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.NullUUID
+	Kind        string
+	Target      string
+	Template    string
+	MaxAttempts int32
+}
+
+func (q *Queries) CreateHook(ctx context.Context, arg CreateHookParams) (Hook, error) {
+	row := q.db.QueryRowContext(ctx, createHook,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UpdatedAt,
+		arg.UserID,
+		arg.FeedID,
+		arg.Kind,
+		arg.Target,
+		arg.Template,
+		arg.MaxAttempts,
+	)
+	var i Hook
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.Kind,
+		&i.Target,
+		&i.Template,
+		&i.MaxAttempts,
+	)
+	return i, err
+}
+
+const createHookDelivery = `-- name: CreateHookDelivery :one
+INSERT INTO hook_deliveries (id, created_at, hook_id, post_id, attempts, succeeded, error)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING id, created_at, hook_id, post_id, attempts, succeeded, error
+`
+
+type CreateHookDeliveryParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	HookID    uuid.UUID
+	PostID    uuid.UUID
+	Attempts  int32
+	Succeeded bool
+	Error     sql.NullString
+}
+
+func (q *Queries) CreateHookDelivery(ctx context.Context, arg CreateHookDeliveryParams) (HookDelivery, error) {
+	row := q.db.QueryRowContext(ctx, createHookDelivery,
+		arg.ID,
+		arg.CreatedAt,
+		arg.HookID,
+		arg.PostID,
+		arg.Attempts,
+		arg.Succeeded,
+		arg.Error,
+	)
+	var i HookDelivery
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.HookID,
+		&i.PostID,
+		&i.Attempts,
+		&i.Succeeded,
+		&i.Error,
+	)
+	return i, err
+}
+
+const deleteHook = `-- name: DeleteHook :execrows
+DELETE FROM hooks
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteHookParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteHook(ctx context.Context, arg DeleteHookParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteHook, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getHookDeliveriesForUser = `-- name: GetHookDeliveriesForUser :many
+SELECT hook_deliveries.id, hook_deliveries.created_at, hook_deliveries.hook_id, hook_deliveries.post_id, hook_deliveries.attempts, hook_deliveries.succeeded, hook_deliveries.error, hooks.kind AS hook_kind, hooks.target AS hook_target, posts.title AS post_title
+FROM hook_deliveries
+INNER JOIN hooks ON hook_deliveries.hook_id = hooks.id
+INNER JOIN posts ON hook_deliveries.post_id = posts.id
+WHERE hooks.user_id = $1
+ORDER BY hook_deliveries.created_at DESC
+LIMIT $2
+`
+
+type GetHookDeliveriesForUserParams struct {
+	UserID uuid.UUID
+	Limit  int32
+}
+
+type GetHookDeliveriesForUserRow struct {
+	ID         uuid.UUID
+	CreatedAt  time.Time
+	HookID     uuid.UUID
+	PostID     uuid.UUID
+	Attempts   int32
+	Succeeded  bool
+	Error      sql.NullString
+	HookKind   string
+	HookTarget string
+	PostTitle  string
+}
+
+func (q *Queries) GetHookDeliveriesForUser(ctx context.Context, arg GetHookDeliveriesForUserParams) ([]GetHookDeliveriesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHookDeliveriesForUser, arg.UserID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetHookDeliveriesForUserRow
+	for rows.Next() {
+		var i GetHookDeliveriesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.HookID,
+			&i.PostID,
+			&i.Attempts,
+			&i.Succeeded,
+			&i.Error,
+			&i.HookKind,
+			&i.HookTarget,
+			&i.PostTitle,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHooksForFeed = `-- name: GetHooksForFeed :many
+SELECT hooks.id, hooks.created_at, hooks.updated_at, hooks.user_id, hooks.feed_id, hooks.kind, hooks.target, hooks.template, hooks.max_attempts FROM hooks
+WHERE hooks.feed_id = $1::uuid
+OR (hooks.feed_id IS NULL AND EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.user_id = hooks.user_id
+    AND feed_follows.feed_id = $1::uuid
+))
+`
+
+// hooks without a feed fire for every feed their user follows
+func (q *Queries) GetHooksForFeed(ctx context.Context, feedID uuid.UUID) ([]Hook, error) {
+	rows, err := q.db.QueryContext(ctx, getHooksForFeed, feedID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Hook
+	for rows.Next() {
+		var i Hook
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+			&i.Kind,
+			&i.Target,
+			&i.Template,
+			&i.MaxAttempts,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getHooksForUser = `-- name: GetHooksForUser :many
+SELECT hooks.id, hooks.created_at, hooks.updated_at, hooks.user_id, hooks.feed_id, hooks.kind, hooks.target, hooks.template, hooks.max_attempts, feeds.name AS feed_name
+FROM hooks
+LEFT JOIN feeds ON hooks.feed_id = feeds.id
+WHERE hooks.user_id = $1
+ORDER BY hooks.created_at
+`
+
+type GetHooksForUserRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.NullUUID
+	Kind        string
+	Target      string
+	Template    string
+	MaxAttempts int32
+	FeedName    sql.NullString
+}
+
+func (q *Queries) GetHooksForUser(ctx context.Context, userID uuid.UUID) ([]GetHooksForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getHooksForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetHooksForUserRow
+	for rows.Next() {
+		var i GetHooksForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.UserID,
+			&i.FeedID,
+			&i.Kind,
+			&i.Target,
+			&i.Template,
+			&i.MaxAttempts,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -12,21 +12,75 @@ import (
 )
 
 type Feed struct {
-	ID            uuid.UUID
-	CreatedAt     time.Time
-	UpdatedAt     time.Time
-	LastFetchedAt sql.NullTime
-	Name          string
-	Url           string
-	UserID        uuid.UUID
+	ID                     uuid.UUID
+	CreatedAt              time.Time
+	UpdatedAt              time.Time
+	LastFetchedAt          sql.NullTime
+	Name                   string
+	Url                    string
+	UserID                 uuid.NullUUID
+	RetentionKeepLast      sql.NullInt32
+	RetentionMaxAgeSeconds sql.NullInt64
+	RetentionKeepUnread    sql.NullBool
 }
 
 type FeedFollow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.UUID
+	DisplayName sql.NullString
+	Notes       sql.NullString
+}
+
+type FeedFollowFolder struct {
+	FeedFollowID uuid.UUID
+	FolderID     uuid.UUID
+}
+
+type FeedPost struct {
+	PostID uuid.UUID
+	FeedID uuid.UUID
+}
+
+type Folder struct {
 	ID        uuid.UUID
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	UserID    uuid.UUID
-	FeedID    uuid.UUID
+	Name      string
+}
+
+type Hook struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	UserID      uuid.UUID
+	FeedID      uuid.NullUUID
+	Kind        string
+	Target      string
+	Template    string
+	MaxAttempts int32
+}
+
+type HookDelivery struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	HookID    uuid.UUID
+	PostID    uuid.UUID
+	Attempts  int32
+	Succeeded bool
+	Error     sql.NullString
+}
+
+type Mute struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Kind      string
+	Pattern   string
 }
 
 type Post struct {
@@ -38,6 +92,69 @@ type Post struct {
 	Description sql.NullString
 	PublishedAt time.Time
 	FeedID      uuid.UUID
+	Guid        sql.NullString
+	Content     sql.NullString
+	ContentHash string
+	RevisedAt   sql.NullTime
+	Simhash     int64
+	ClusterID   uuid.NullUUID
+	Author      string
+	Categories  string
+}
+
+type PostRead struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+type PostRevision struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	PostID      uuid.UUID
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+	ContentHash string
+}
+
+type PostSighting struct {
+	PostID uuid.UUID
+	FeedID uuid.UUID
+	SeenAt time.Time
+}
+
+type PostStar struct {
+	UserID    uuid.UUID
+	PostID    uuid.UUID
+	StarredAt time.Time
+}
+
+type ReadLater struct {
+	UserID   uuid.UUID
+	PostID   uuid.UUID
+	Position int32
+	AddedAt  time.Time
+}
+
+type SearchablePost struct {
+	ID           uuid.UUID
+	CreatedAt    time.Time
+	UpdatedAt    time.Time
+	Title        string
+	Url          string
+	Description  sql.NullString
+	PublishedAt  time.Time
+	FeedID       uuid.UUID
+	Guid         sql.NullString
+	Content      sql.NullString
+	ContentHash  string
+	RevisedAt    sql.NullTime
+	SearchVector string
+	Simhash      int64
+	ClusterID    uuid.NullUUID
+	Author       string
+	Categories   string
 }
 
 type User struct {
@@ -45,4 +162,6 @@ type User struct {
 	CreatedAt time.Time
 	UpdatedAt time.Time
 	Name      string
+	IsAdmin   bool
+	Timezone  string
 }
@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: mutes.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createMute = `-- name: CreateMute :one
+INSERT INTO mutes (id, created_at, user_id, feed_id, kind, pattern)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING id, created_at, user_id, feed_id, kind, pattern
+`
+
+type CreateMuteParams struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Kind      string
+	Pattern   string
+}
+
+func (q *Queries) CreateMute(ctx context.Context, arg CreateMuteParams) (Mute, error) {
+	row := q.db.QueryRowContext(ctx, createMute,
+		arg.ID,
+		arg.CreatedAt,
+		arg.UserID,
+		arg.FeedID,
+		arg.Kind,
+		arg.Pattern,
+	)
+	var i Mute
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UserID,
+		&i.FeedID,
+		&i.Kind,
+		&i.Pattern,
+	)
+	return i, err
+}
+
+const deleteMute = `-- name: DeleteMute :execrows
+DELETE FROM mutes
+WHERE id = $1 AND user_id = $2
+`
+
+type DeleteMuteParams struct {
+	ID     uuid.UUID
+	UserID uuid.UUID
+}
+
+func (q *Queries) DeleteMute(ctx context.Context, arg DeleteMuteParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteMute, arg.ID, arg.UserID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getMutesForUser = `-- name: GetMutesForUser :many
+SELECT mutes.id, mutes.created_at, mutes.user_id, mutes.feed_id, mutes.kind, mutes.pattern, feeds.name AS feed_name
+FROM mutes
+LEFT JOIN feeds ON mutes.feed_id = feeds.id
+WHERE mutes.user_id = $1
+ORDER BY mutes.created_at, mutes.id
+`
+
+type GetMutesForUserRow struct {
+	ID        uuid.UUID
+	CreatedAt time.Time
+	UserID    uuid.UUID
+	FeedID    uuid.NullUUID
+	Kind      string
+	Pattern   string
+	FeedName  sql.NullString
+}
+
+func (q *Queries) GetMutesForUser(ctx context.Context, userID uuid.UUID) ([]GetMutesForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getMutesForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetMutesForUserRow
+	for rows.Next() {
+		var i GetMutesForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UserID,
+			&i.FeedID,
+			&i.Kind,
+			&i.Pattern,
+			&i.FeedName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
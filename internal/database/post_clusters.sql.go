@@ -0,0 +1,146 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: post_clusters.sql
+
+package database
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addPostSighting = `-- name: AddPostSighting :exec
+INSERT INTO post_sightings (post_id, feed_id, seen_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, feed_id) DO NOTHING
+`
+
+type AddPostSightingParams struct {
+	PostID uuid.UUID
+	FeedID uuid.UUID
+	SeenAt time.Time
+}
+
+func (q *Queries) AddPostSighting(ctx context.Context, arg AddPostSightingParams) error {
+	_, err := q.db.ExecContext(ctx, addPostSighting, arg.PostID, arg.FeedID, arg.SeenAt)
+	return err
+}
+
+const getClusterCandidates = `-- name: GetClusterCandidates :many
+SELECT id, cluster_id, simhash FROM posts
+WHERE feed_id <> $1
+AND simhash <> 0
+AND published_at >= $2
+AND published_at < $3
+ORDER BY created_at, id
+`
+
+type GetClusterCandidatesParams struct {
+	FeedID uuid.UUID
+	Since  time.Time
+	Until  time.Time
+}
+
+type GetClusterCandidatesRow struct {
+	ID        uuid.UUID
+	ClusterID uuid.NullUUID
+	Simhash   int64
+}
+
+// fingerprinted posts of other feeds published around the same time, oldest first
+func (q *Queries) GetClusterCandidates(ctx context.Context, arg GetClusterCandidatesParams) ([]GetClusterCandidatesRow, error) {
+	rows, err := q.db.QueryContext(ctx, getClusterCandidates, arg.FeedID, arg.Since, arg.Until)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetClusterCandidatesRow
+	for rows.Next() {
+		var i GetClusterCandidatesRow
+		if err := rows.Scan(&i.ID, &i.ClusterID, &i.Simhash); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getClusterFeedNames = `-- name: GetClusterFeedNames :many
+SELECT DISTINCT COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name
+FROM feeds
+LEFT JOIN feed_follows ON feed_follows.feed_id = feeds.id AND feed_follows.user_id = $1
+WHERE feeds.id <> $2
+AND (
+    feeds.id IN (
+        SELECT posts.feed_id FROM posts
+        WHERE posts.id = $3 OR posts.cluster_id = $4::uuid
+    )
+    OR feeds.id IN (
+        SELECT post_sightings.feed_id FROM post_sightings
+        JOIN posts ON posts.id = post_sightings.post_id
+        WHERE posts.id = $3 OR posts.cluster_id = $4::uuid
+    )
+)
+ORDER BY feed_name
+`
+
+type GetClusterFeedNamesParams struct {
+	UserID    uuid.UUID
+	FeedID    uuid.UUID
+	PostID    uuid.UUID
+	ClusterID uuid.NullUUID
+}
+
+// the other feeds a post's story showed up in, named the way the user sees them
+func (q *Queries) GetClusterFeedNames(ctx context.Context, arg GetClusterFeedNamesParams) ([]string, error) {
+	rows, err := q.db.QueryContext(ctx, getClusterFeedNames,
+		arg.UserID,
+		arg.FeedID,
+		arg.PostID,
+		arg.ClusterID,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []string
+	for rows.Next() {
+		var feed_name string
+		if err := rows.Scan(&feed_name); err != nil {
+			return nil, err
+		}
+		items = append(items, feed_name)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const setPostCluster = `-- name: SetPostCluster :exec
+UPDATE posts
+SET cluster_id = $2
+WHERE id = $1
+`
+
+type SetPostClusterParams struct {
+	ID        uuid.UUID
+	ClusterID uuid.NullUUID
+}
+
+func (q *Queries) SetPostCluster(ctx context.Context, arg SetPostClusterParams) error {
+	_, err := q.db.ExecContext(ctx, setPostCluster, arg.ID, arg.ClusterID)
+	return err
+}
@@ -0,0 +1,139 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: post_reads.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const getReadPostsForUser = `-- name: GetReadPostsForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content, posts.content_hash, posts.revised_at, posts.simhash, posts.cluster_id, posts.author, posts.categories, post_reads.read_at FROM posts
+INNER JOIN post_reads ON post_reads.post_id = posts.id
+WHERE post_reads.user_id = $1
+ORDER BY post_reads.read_at DESC
+`
+
+type GetReadPostsForUserRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+	Guid        sql.NullString
+	Content     sql.NullString
+	ContentHash string
+	RevisedAt   sql.NullTime
+	Simhash     int64
+	ClusterID   uuid.NullUUID
+	Author      string
+	Categories  string
+	ReadAt      time.Time
+}
+
+func (q *Queries) GetReadPostsForUser(ctx context.Context, userID uuid.UUID) ([]GetReadPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadPostsForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadPostsForUserRow
+	for rows.Next() {
+		var i GetReadPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Guid,
+			&i.Content,
+			&i.ContentHash,
+			&i.RevisedAt,
+			&i.Simhash,
+			&i.ClusterID,
+			&i.Author,
+			&i.Categories,
+			&i.ReadAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const markAllPostsRead = `-- name: MarkAllPostsRead :execrows
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT feed_follows.user_id, feed_posts.post_id, $1
+FROM feed_posts
+INNER JOIN feed_follows ON feed_posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = $2
+AND ($3::uuid IS NULL OR feed_posts.feed_id = $3::uuid)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkAllPostsReadParams struct {
+	ReadAt time.Time
+	UserID uuid.UUID
+	FeedID uuid.NullUUID
+}
+
+// without a feed every followed feed is marked, posts sighted in a feed count as its own
+func (q *Queries) MarkAllPostsRead(ctx context.Context, arg MarkAllPostsReadParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markAllPostsRead, arg.ReadAt, arg.UserID, arg.FeedID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const markPostRead = `-- name: MarkPostRead :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type MarkPostReadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+	ReadAt time.Time
+}
+
+func (q *Queries) MarkPostRead(ctx context.Context, arg MarkPostReadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostRead, arg.UserID, arg.PostID, arg.ReadAt)
+	return err
+}
+
+const markPostUnread = `-- name: MarkPostUnread :exec
+DELETE FROM post_reads
+WHERE user_id = $1 AND post_id = $2
+`
+
+type MarkPostUnreadParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) MarkPostUnread(ctx context.Context, arg MarkPostUnreadParams) error {
+	_, err := q.db.ExecContext(ctx, markPostUnread, arg.UserID, arg.PostID)
+	return err
+}
@@ -0,0 +1,83 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: post_revisions.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const createPostRevision = `-- name: CreatePostRevision :one
+INSERT INTO post_revisions (id, created_at, post_id, title, description, content, content_hash)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING id, created_at, post_id, title, description, content, content_hash
+`
+
+type CreatePostRevisionParams struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	PostID      uuid.UUID
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+	ContentHash string
+}
+
+func (q *Queries) CreatePostRevision(ctx context.Context, arg CreatePostRevisionParams) (PostRevision, error) {
+	row := q.db.QueryRowContext(ctx, createPostRevision,
+		arg.ID,
+		arg.CreatedAt,
+		arg.PostID,
+		arg.Title,
+		arg.Description,
+		arg.Content,
+		arg.ContentHash,
+	)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.PostID,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+		&i.ContentHash,
+	)
+	return i, err
+}
+
+const getLatestPostRevision = `-- name: GetLatestPostRevision :one
+SELECT id, created_at, post_id, title, description, content, content_hash FROM post_revisions
+WHERE post_id = $1
+ORDER BY created_at DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestPostRevision(ctx context.Context, postID uuid.UUID) (PostRevision, error) {
+	row := q.db.QueryRowContext(ctx, getLatestPostRevision, postID)
+	var i PostRevision
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.PostID,
+		&i.Title,
+		&i.Description,
+		&i.Content,
+		&i.ContentHash,
+	)
+	return i, err
+}
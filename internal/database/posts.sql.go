@@ -11,10 +11,11 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 const createPost = `-- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, simhash, author, categories)
 VALUES (
     $1,
     $2,
@@ -23,9 +24,15 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
 )
-RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories
 `
 
 type CreatePostParams struct {
@@ -37,6 +44,12 @@ type CreatePostParams struct {
 	Description sql.NullString
 	PublishedAt time.Time
 	FeedID      uuid.UUID
+	Guid        sql.NullString
+	Content     sql.NullString
+	ContentHash string
+	Simhash     int64
+	Author      string
+	Categories  string
 }
 
 func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, error) {
@@ -49,6 +62,12 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		arg.Description,
 		arg.PublishedAt,
 		arg.FeedID,
+		arg.Guid,
+		arg.Content,
+		arg.ContentHash,
+		arg.Simhash,
+		arg.Author,
+		arg.Categories,
 	)
 	var i Post
 	err := row.Scan(
@@ -60,25 +79,360 @@ func (q *Queries) CreatePost(ctx context.Context, arg CreatePostParams) (Post, e
 		&i.Description,
 		&i.PublishedAt,
 		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const deletePostsByIDs = `-- name: DeletePostsByIDs :exec
+DELETE FROM posts
+WHERE id = ANY($1::uuid[])
+`
+
+func (q *Queries) DeletePostsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	_, err := q.db.ExecContext(ctx, deletePostsByIDs, pq.Array(ids))
+	return err
+}
+
+const getLatestPostForFeed = `-- name: GetLatestPostForFeed :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC, id DESC
+LIMIT 1
+`
+
+func (q *Queries) GetLatestPostForFeed(ctx context.Context, feedID uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getLatestPostForFeed, feedID)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostByFeedGUID = `-- name: GetPostByFeedGUID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories FROM posts
+WHERE feed_id = $1 AND guid = $2 LIMIT 1
+`
+
+type GetPostByFeedGUIDParams struct {
+	FeedID uuid.UUID
+	Guid   sql.NullString
+}
+
+func (q *Queries) GetPostByFeedGUID(ctx context.Context, arg GetPostByFeedGUIDParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByFeedGUID, arg.FeedID, arg.Guid)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostByID = `-- name: GetPostByID :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories FROM posts
+WHERE id = $1 LIMIT 1
+`
+
+func (q *Queries) GetPostByID(ctx context.Context, id uuid.UUID) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByID, id)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
+
+const getPostByURL = `-- name: GetPostByURL :one
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories FROM posts
+WHERE url = $1 LIMIT 1
+`
+
+func (q *Queries) GetPostByURL(ctx context.Context, url string) (Post, error) {
+	row := q.db.QueryRowContext(ctx, getPostByURL, url)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
 	)
 	return i, err
 }
 
 const getPostsForUser = `-- name: GetPostsForUser :many
-SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id FROM posts
-JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
-LIMIT $2
+WITH browsable AS NOT MATERIALIZED (
+    SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content, posts.content_hash, posts.revised_at, posts.simhash, posts.cluster_id, posts.author, posts.categories, (post_reads.post_id IS NOT NULL)::bool AS is_read,
+        COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name, feeds.id AS browsed_feed_id
+    FROM feed_posts
+    JOIN posts ON posts.id = feed_posts.post_id
+    JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+    JOIN feeds ON feeds.id = feed_posts.feed_id
+    LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = feed_follows.user_id
+    WHERE feed_follows.user_id = $1
+    AND (NOT $2::bool OR post_reads.post_id IS NULL)
+    AND ($3::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_follow_folders
+        WHERE feed_follow_folders.feed_follow_id = feed_follows.id
+        AND feed_follow_folders.folder_id = $3::uuid
+    ))
+    AND ($4::uuid IS NULL OR feed_posts.feed_id = $4::uuid)
+    AND ($5::timestamptz IS NULL OR posts.published_at >= $5::timestamptz)
+    AND ($6::timestamptz IS NULL OR posts.published_at < $6::timestamptz)
+    AND ($7::text IS NULL OR posts.title ILIKE '%' || $7::text || '%')
+),
+shown AS NOT MATERIALIZED (
+    SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories, is_read, feed_name, browsed_feed_id FROM browsable
+    WHERE NOT EXISTS (
+        SELECT 1 FROM browsable AS other
+        WHERE other.id = browsable.id
+        AND (other.browsed_feed_id <> other.feed_id, other.browsed_feed_id) < (browsable.browsed_feed_id <> browsable.feed_id, browsable.browsed_feed_id)
+    )
+    AND (browsable.cluster_id IS NULL OR NOT EXISTS (
+        SELECT 1 FROM browsable AS earlier
+        WHERE earlier.cluster_id = browsable.cluster_id
+        AND (earlier.created_at, earlier.id) < (browsable.created_at, browsable.id)
+    ))
+)
+(
+    SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories, is_read, feed_name, browsed_feed_id FROM shown
+    WHERE NOT $8::bool AND NOT $9::bool
+    AND ($10::timestamptz IS NULL OR (published_at, id) < ($10::timestamptz, $11::uuid))
+    ORDER BY published_at DESC, id DESC
+    LIMIT $12
+)
+UNION ALL
+(
+    SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories, is_read, feed_name, browsed_feed_id FROM shown
+    WHERE NOT $8::bool AND $9::bool
+    AND ($10::timestamptz IS NULL OR (published_at, id) > ($10::timestamptz, $11::uuid))
+    ORDER BY published_at ASC, id ASC
+    LIMIT $12
+)
+UNION ALL
+(
+    SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories, is_read, feed_name, browsed_feed_id FROM shown
+    WHERE $8::bool AND NOT $9::bool
+    AND ($10::timestamptz IS NULL OR (created_at, id) < ($10::timestamptz, $11::uuid))
+    ORDER BY created_at DESC, id DESC
+    LIMIT $12
+)
+UNION ALL
+(
+    SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories, is_read, feed_name, browsed_feed_id FROM shown
+    WHERE $8::bool AND $9::bool
+    AND ($10::timestamptz IS NULL OR (created_at, id) > ($10::timestamptz, $11::uuid))
+    ORDER BY created_at ASC, id ASC
+    LIMIT $12
+)
 `
 
 type GetPostsForUserParams struct {
-	UserID uuid.UUID
-	Limit  int32
+	UserID        uuid.UUID
+	UnreadOnly    bool
+	FolderID      uuid.NullUUID
+	FeedID        uuid.NullUUID
+	Since         sql.NullTime
+	Until         sql.NullTime
+	Title         sql.NullString
+	SortByFetched bool
+	Reverse       bool
+	CursorTime    sql.NullTime
+	CursorID      uuid.UUID
+	Limit         int32
+}
+
+type GetPostsForUserRow struct {
+	ID            uuid.UUID
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+	Title         string
+	Url           string
+	Description   sql.NullString
+	PublishedAt   time.Time
+	FeedID        uuid.UUID
+	Guid          sql.NullString
+	Content       sql.NullString
+	ContentHash   string
+	RevisedAt     sql.NullTime
+	Simhash       int64
+	ClusterID     uuid.NullUUID
+	Author        string
+	Categories    string
+	IsRead        bool
+	FeedName      string
+	BrowsedFeedID uuid.UUID
+}
+
+// keyset paginated by publish or fetch time, newest first unless reverse, the rows after the cursor when one is given.
+// The filters are only written in browsable, each branch below is one sort and direction and only
+// the one picked by sort_by_fetched and reverse returns rows.
+// Posts sighted in a followed feed are shown through it too, once per post:
+// through the post's own feed when it is followed, else through the followed feed with the lowest id that sighted it.
+// A story clustered over several feeds is shown once, by its earliest fetched post among the browsed ones.
+func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]GetPostsForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getPostsForUser,
+		arg.UserID,
+		arg.UnreadOnly,
+		arg.FolderID,
+		arg.FeedID,
+		arg.Since,
+		arg.Until,
+		arg.Title,
+		arg.SortByFetched,
+		arg.Reverse,
+		arg.CursorTime,
+		arg.CursorID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetPostsForUserRow
+	for rows.Next() {
+		var i GetPostsForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Guid,
+			&i.Content,
+			&i.ContentHash,
+			&i.RevisedAt,
+			&i.Simhash,
+			&i.ClusterID,
+			&i.Author,
+			&i.Categories,
+			&i.IsRead,
+			&i.FeedName,
+			&i.BrowsedFeedID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams) ([]Post, error) {
-	rows, err := q.db.QueryContext(ctx, getPostsForUser, arg.UserID, arg.Limit)
+const getPrunablePosts = `-- name: GetPrunablePosts :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content, posts.content_hash, posts.revised_at, posts.simhash, posts.cluster_id, posts.author, posts.categories FROM posts
+WHERE posts.feed_id = $1
+AND (
+    ($2::int IS NOT NULL AND posts.id NOT IN (
+        SELECT newest.id FROM posts AS newest
+        WHERE newest.feed_id = $1
+        ORDER BY newest.published_at DESC, newest.id DESC
+        LIMIT $2::int
+    ))
+    OR ($3::timestamptz IS NOT NULL AND posts.published_at < $3::timestamptz)
+)
+AND (NOT $4::bool OR NOT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.feed_id = posts.feed_id
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id
+        AND post_reads.user_id = feed_follows.user_id
+    )
+))
+AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+AND NOT EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id)
+ORDER BY posts.published_at
+`
+
+type GetPrunablePostsParams struct {
+	FeedID     uuid.UUID
+	KeepLast   sql.NullInt32
+	OlderThan  sql.NullTime
+	KeepUnread bool
+}
+
+// keep_last and older_than are independent limits, a post breaking any of them can be pruned
+// unless keep_unread protects posts some follower of the feed hasn't read yet
+// starred posts and posts in a read later queue are never pruned
+func (q *Queries) GetPrunablePosts(ctx context.Context, arg GetPrunablePostsParams) ([]Post, error) {
+	rows, err := q.db.QueryContext(ctx, getPrunablePosts,
+		arg.FeedID,
+		arg.KeepLast,
+		arg.OlderThan,
+		arg.KeepUnread,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -95,6 +449,14 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 			&i.Description,
 			&i.PublishedAt,
 			&i.FeedID,
+			&i.Guid,
+			&i.Content,
+			&i.ContentHash,
+			&i.RevisedAt,
+			&i.Simhash,
+			&i.ClusterID,
+			&i.Author,
+			&i.Categories,
 		); err != nil {
 			return nil, err
 		}
@@ -108,3 +470,63 @@ func (q *Queries) GetPostsForUser(ctx context.Context, arg GetPostsForUserParams
 	}
 	return items, nil
 }
+
+const updatePostContent = `-- name: UpdatePostContent :one
+UPDATE posts
+SET title = $2,
+    description = $3,
+    content = $4,
+    content_hash = $5,
+    guid = $6,
+    updated_at = $7,
+    url = $8,
+    revised_at = COALESCE($9, revised_at)
+WHERE id = $1
+RETURNING id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories
+`
+
+type UpdatePostContentParams struct {
+	ID          uuid.UUID
+	Title       string
+	Description sql.NullString
+	Content     sql.NullString
+	ContentHash string
+	Guid        sql.NullString
+	UpdatedAt   time.Time
+	Url         string
+	RevisedAt   sql.NullTime
+}
+
+func (q *Queries) UpdatePostContent(ctx context.Context, arg UpdatePostContentParams) (Post, error) {
+	row := q.db.QueryRowContext(ctx, updatePostContent,
+		arg.ID,
+		arg.Title,
+		arg.Description,
+		arg.Content,
+		arg.ContentHash,
+		arg.Guid,
+		arg.UpdatedAt,
+		arg.Url,
+		arg.RevisedAt,
+	)
+	var i Post
+	err := row.Scan(
+		&i.ID,
+		&i.CreatedAt,
+		&i.UpdatedAt,
+		&i.Title,
+		&i.Url,
+		&i.Description,
+		&i.PublishedAt,
+		&i.FeedID,
+		&i.Guid,
+		&i.Content,
+		&i.ContentHash,
+		&i.RevisedAt,
+		&i.Simhash,
+		&i.ClusterID,
+		&i.Author,
+		&i.Categories,
+	)
+	return i, err
+}
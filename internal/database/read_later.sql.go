@@ -0,0 +1,124 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: read_later.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const addToReadLater = `-- name: AddToReadLater :exec
+INSERT INTO read_later (user_id, post_id, position, added_at)
+VALUES (
+    $1,
+    $2,
+    COALESCE((SELECT MAX(position) FROM read_later WHERE read_later.user_id = $1), 0) + 1,
+    $3
+)
+ON CONFLICT (user_id, post_id) DO NOTHING
+`
+
+type AddToReadLaterParams struct {
+	UserID  uuid.UUID
+	PostID  uuid.UUID
+	AddedAt time.Time
+}
+
+func (q *Queries) AddToReadLater(ctx context.Context, arg AddToReadLaterParams) error {
+	_, err := q.db.ExecContext(ctx, addToReadLater, arg.UserID, arg.PostID, arg.AddedAt)
+	return err
+}
+
+const getReadLaterForUser = `-- name: GetReadLaterForUser :many
+SELECT posts.id, posts.created_at, posts.updated_at, posts.title, posts.url, posts.description, posts.published_at, posts.feed_id, posts.guid, posts.content, posts.content_hash, posts.revised_at, posts.simhash, posts.cluster_id, posts.author, posts.categories, read_later.position, read_later.added_at FROM posts
+INNER JOIN read_later ON read_later.post_id = posts.id
+WHERE read_later.user_id = $1
+ORDER BY read_later.position
+`
+
+type GetReadLaterForUserRow struct {
+	ID          uuid.UUID
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	Title       string
+	Url         string
+	Description sql.NullString
+	PublishedAt time.Time
+	FeedID      uuid.UUID
+	Guid        sql.NullString
+	Content     sql.NullString
+	ContentHash string
+	RevisedAt   sql.NullTime
+	Simhash     int64
+	ClusterID   uuid.NullUUID
+	Author      string
+	Categories  string
+	Position    int32
+	AddedAt     time.Time
+}
+
+func (q *Queries) GetReadLaterForUser(ctx context.Context, userID uuid.UUID) ([]GetReadLaterForUserRow, error) {
+	rows, err := q.db.QueryContext(ctx, getReadLaterForUser, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []GetReadLaterForUserRow
+	for rows.Next() {
+		var i GetReadLaterForUserRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+			&i.Title,
+			&i.Url,
+			&i.Description,
+			&i.PublishedAt,
+			&i.FeedID,
+			&i.Guid,
+			&i.Content,
+			&i.ContentHash,
+			&i.RevisedAt,
+			&i.Simhash,
+			&i.ClusterID,
+			&i.Author,
+			&i.Categories,
+			&i.Position,
+			&i.AddedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const removeFromReadLater = `-- name: RemoveFromReadLater :execrows
+DELETE FROM read_later
+WHERE user_id = $1 AND post_id = $2
+`
+
+type RemoveFromReadLaterParams struct {
+	UserID uuid.UUID
+	PostID uuid.UUID
+}
+
+func (q *Queries) RemoveFromReadLater(ctx context.Context, arg RemoveFromReadLaterParams) (int64, error) {
+	result, err := q.db.ExecContext(ctx, removeFromReadLater, arg.UserID, arg.PostID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,107 @@
+// Code generated by sqlc. DO NOT EDIT.
+// versions:
+//   sqlc v1.30.0
+// source: search.sql
+
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const searchPosts = `-- name: SearchPosts :many
+WITH matches AS (
+    SELECT posts.id, posts.title, posts.url, posts.description, posts.content, posts.published_at, posts.feed_id,
+        ts_rank(posts.search_vector, to_tsquery('english', $1)) AS rank
+    FROM searchable_posts AS posts
+    WHERE posts.search_vector @@ to_tsquery('english', $1)
+    AND ($2::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_posts
+        INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+        WHERE feed_posts.post_id = posts.id
+        AND feed_follows.user_id = $2::uuid
+    ))
+    AND ($3::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_posts
+        INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+        INNER JOIN feed_follow_folders ON feed_follow_folders.feed_follow_id = feed_follows.id
+        WHERE feed_posts.post_id = posts.id
+        AND feed_follow_folders.folder_id = $3::uuid
+    ))
+    AND ($4::timestamptz IS NULL OR posts.published_at >= $4::timestamptz)
+    AND ($5::timestamptz IS NULL OR posts.published_at < $5::timestamptz)
+    ORDER BY rank DESC, posts.published_at DESC
+    LIMIT $6
+)
+SELECT matches.id, matches.url, matches.published_at, matches.rank, feeds.name AS feed_name,
+    ts_headline('english', matches.title, to_tsquery('english', $1),
+        'HighlightAll=true, StartSel=**, StopSel=**')::text AS title_headline,
+    ts_headline('english', COALESCE(matches.content, matches.description, ''), to_tsquery('english', $1),
+        'MaxFragments=2, MaxWords=25, MinWords=10, StartSel=**, StopSel=**')::text AS snippet
+FROM matches
+INNER JOIN feeds ON feeds.id = matches.feed_id
+ORDER BY matches.rank DESC, matches.published_at DESC
+`
+
+type SearchPostsParams struct {
+	Query    string
+	UserID   uuid.NullUUID
+	FolderID uuid.NullUUID
+	Since    sql.NullTime
+	Until    sql.NullTime
+	Limit    int32
+}
+
+type SearchPostsRow struct {
+	ID            uuid.UUID
+	Url           string
+	PublishedAt   time.Time
+	Rank          float32
+	FeedName      string
+	TitleHeadline string
+	Snippet       string
+}
+
+// without user_id every post is searched, headlines are only built for the returned page
+// posts sighted in a followed feed are searched as well, like browse shows them
+func (q *Queries) SearchPosts(ctx context.Context, arg SearchPostsParams) ([]SearchPostsRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchPosts,
+		arg.Query,
+		arg.UserID,
+		arg.FolderID,
+		arg.Since,
+		arg.Until,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []SearchPostsRow
+	for rows.Next() {
+		var i SearchPostsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Url,
+			&i.PublishedAt,
+			&i.Rank,
+			&i.FeedName,
+			&i.TitleHeadline,
+			&i.Snippet,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
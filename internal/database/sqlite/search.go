@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"database/sql/driver"
+
+	"grysha11/BlogAggregator/internal/search"
+
+	"modernc.org/sqlite"
+)
+
+// SQLite has no tsquery, the search query is evaluated by internal/search instead
+func init() {
+	sqlite.MustRegisterDeterministicScalarFunction("tsquery_match", 2, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		q, err := search.Parse(argText(args[0]))
+		if err != nil {
+			return nil, err
+		}
+		return q.Match(argText(args[1])), nil
+	})
+
+	sqlite.MustRegisterDeterministicScalarFunction("tsquery_rank", 4, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		q, err := search.Parse(argText(args[0]))
+		if err != nil {
+			return nil, err
+		}
+		return q.Rank(argText(args[1]), argText(args[2]), argText(args[3])), nil
+	})
+
+	sqlite.MustRegisterDeterministicScalarFunction("tsquery_headline", 3, func(ctx *sqlite.FunctionContext, args []driver.Value) (driver.Value, error) {
+		q, err := search.Parse(argText(args[0]))
+		if err != nil {
+			return nil, err
+		}
+
+		maxWords, _ := args[2].(int64)
+		return q.Headline(argText(args[1]), int(maxWords)), nil
+	})
+}
+
+func argText(v driver.Value) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case []byte:
+		return string(v)
+	}
+	return ""
+}
@@ -0,0 +1,181 @@
+// Package sqlite runs the generated queries of internal/database against a SQLite file.
+//
+// sqlc only generates code for Postgres, so every query has a SQLite version under the same
+// name in sql/sqlite/queries. DB hands the generated code the SQLite text of the query it runs,
+// binding the arguments and scanning the rows is still done by internal/database.
+package sqlite
+
+import (
+	"bufio"
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	files "grysha11/BlogAggregator/sql/sqlite"
+
+	_ "modernc.org/sqlite"
+)
+
+type DB struct {
+	conn	conn
+	queries	map[string]string
+}
+
+// conn is what queries run on, the database itself or a transaction
+type conn interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	PrepareContext(ctx context.Context, query string) (*sql.Stmt, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// Open opens the SQLite file at path, creating it when it doesn't exist
+func Open(path string) (*sql.DB, error) {
+	conn, err := sql.Open("sqlite", "file:"+path+"?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_time_format=sqlite")
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite has a single writer anyway, one connection keeps parallel fetches from failing with SQLITE_BUSY
+	conn.SetMaxOpenConns(1)
+	return conn, nil
+}
+
+// New wraps conn so it can be passed to database.New.
+// It fails when some generated query has no SQLite version.
+func New(conn *sql.DB) (*DB, error) {
+	queries, err := loadQueries()
+	if err != nil {
+		return nil, err
+	}
+
+	var missing []string
+	queriesType := reflect.TypeOf(&database.Queries{})
+	for i := 0; i < queriesType.NumMethod(); i++ {
+		name := queriesType.Method(i).Name
+		if name == "WithTx" {
+			continue
+		}
+		if _, ok := queries[name]; !ok {
+			missing = append(missing, name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("queries missing for SQLite: %v", strings.Join(missing, ", "))
+	}
+
+	return &DB{
+		conn: conn,
+		queries: queries,
+	}, nil
+}
+
+// WithTx runs the queries inside tx, like database.Queries.WithTx does for Postgres
+func (d *DB) WithTx(tx *sql.Tx) *DB {
+	return &DB{
+		conn: tx,
+		queries: d.queries,
+	}
+}
+
+func loadQueries() (map[string]string, error) {
+	queries := make(map[string]string)
+
+	paths, err := fs.Glob(files.Queries, "queries/*.sql")
+	if err != nil {
+		return nil, err
+	}
+	slices.Sort(paths)
+
+	for _, path := range paths {
+		data, err := files.Queries.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		var name string
+		var body strings.Builder
+		flush := func() {
+			if name != "" {
+				queries[name] = strings.TrimSpace(body.String())
+			}
+			body.Reset()
+		}
+
+		scanner := bufio.NewScanner(strings.NewReader(string(data)))
+		for scanner.Scan() {
+			line := scanner.Text()
+			if queryName, ok := parseQueryName(line); ok {
+				flush()
+				name = queryName
+			}
+			body.WriteString(line)
+			body.WriteString("\n")
+		}
+		flush()
+	}
+
+	return queries, nil
+}
+
+// parseQueryName reads the name out of a "-- name: CreateUser :one" line,
+// sqlc starts every generated query with it as well
+func parseQueryName(line string) (string, bool) {
+	rest, ok := strings.CutPrefix(strings.TrimSpace(line), "-- name:")
+	if !ok {
+		return "", false
+	}
+
+	fields := strings.Fields(rest)
+	if len(fields) == 0 {
+		return "", false
+	}
+	return fields[0], true
+}
+
+func (d *DB) translate(query string) string {
+	firstLine, _, _ := strings.Cut(query, "\n")
+	if name, ok := parseQueryName(firstLine); ok {
+		if sqliteQuery, ok := d.queries[name]; ok {
+			return sqliteQuery
+		}
+	}
+	return query
+}
+
+// utcArgs stores every time in UTC, SQLite compares times as text
+func utcArgs(args []interface{}) []interface{} {
+	for i, arg := range args {
+		switch v := arg.(type) {
+		case time.Time:
+			args[i] = v.UTC()
+		case sql.NullTime:
+			if v.Valid {
+				args[i] = sql.NullTime{Time: v.Time.UTC(), Valid: true}
+			}
+		}
+	}
+	return args
+}
+
+func (d *DB) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return d.conn.ExecContext(ctx, d.translate(query), utcArgs(args)...)
+}
+
+func (d *DB) PrepareContext(ctx context.Context, query string) (*sql.Stmt, error) {
+	return d.conn.PrepareContext(ctx, d.translate(query))
+}
+
+func (d *DB) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return d.conn.QueryContext(ctx, d.translate(query), utcArgs(args)...)
+}
+
+func (d *DB) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return d.conn.QueryRowContext(ctx, d.translate(query), utcArgs(args)...)
+}
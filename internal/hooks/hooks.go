@@ -0,0 +1,118 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os/exec"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	KindWebhook	= "webhook"
+	KindCommand	= "command"
+)
+
+// Templates are the webhook payload shapes, "json" sends the post as it is
+var Templates = []string{"json", "slack", "discord", "matrix"}
+
+const deliveryTimeout = 30 * time.Second
+
+type Feed struct {
+	ID		uuid.UUID	`json:"id"`
+	Name	string		`json:"name"`
+	URL		string		`json:"url"`
+}
+
+// Post is what hooks receive about a new post
+type Post struct {
+	ID			uuid.UUID	`json:"id"`
+	Title		string		`json:"title"`
+	URL			string		`json:"url"`
+	Description	string		`json:"description,omitempty"`
+	PublishedAt	time.Time	`json:"published_at"`
+	Feed		Feed		`json:"feed"`
+}
+
+// Render builds the webhook body for post in the given template
+func Render(template string, post Post) ([]byte, error) {
+	text := fmt.Sprintf("%v: %v\n%v", post.Feed.Name, post.Title, post.URL)
+
+	var payload any
+	switch template {
+	case "json":
+		payload = post
+	case "slack":
+		payload = map[string]string{
+			"text": fmt.Sprintf("*%v*: <%v|%v>", post.Feed.Name, post.URL, post.Title),
+		}
+	case "discord":
+		payload = map[string]string{
+			"content": fmt.Sprintf("**%v**: %v\n%v", post.Feed.Name, post.Title, post.URL),
+		}
+	case "matrix":
+		payload = map[string]string{
+			"msgtype": "m.text",
+			"text": text,
+			"body": text,
+		}
+	default:
+		return nil, fmt.Errorf("unknown hook template: %v", template)
+	}
+
+	return json.Marshal(payload)
+}
+
+// SendWebhook posts body to url and fails on any non 2xx answer
+func SendWebhook(ctx context.Context, url, userAgent string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, "POST", url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", userAgent)
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook answered with status code %v", resp.StatusCode)
+	}
+	return nil
+}
+
+// RunCommand runs command through the shell with post as JSON on its stdin
+func RunCommand(ctx context.Context, command string, post Post) error {
+	ctx, cancel := context.WithTimeout(ctx, deliveryTimeout)
+	defer cancel()
+
+	data, err := json.Marshal(post)
+	if err != nil {
+		return err
+	}
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(data)
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		output := strings.TrimSpace(string(out))
+		if output != "" {
+			return fmt.Errorf("%v: %v", err, output)
+		}
+		return err
+	}
+	return nil
+}
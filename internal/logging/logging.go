@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+// New builds the logger used by the aggregator and the CLI.
+// Logs go to file when it is set, otherwise to out (stderr or io.Discard, never stdout
+// so they don't mix with command output).
+// The returned close func releases the log file and is safe to call when there is none.
+func New(level, format, file string, out io.Writer) (*slog.Logger, func() error, error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	closeFn := func() error { return nil }
+	if file != "" {
+		f, err := os.OpenFile(file, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, nil, fmt.Errorf("couldn't open log file: %v", err)
+		}
+		out = f
+		closeFn = f.Close
+	}
+
+	opts := &slog.HandlerOptions{Level: lvl}
+
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", "text":
+		handler = slog.NewTextHandler(out, opts)
+	case "json":
+		handler = slog.NewJSONHandler(out, opts)
+	default:
+		closeFn()
+		return nil, nil, fmt.Errorf("unknown log format: %v (expected text or json)", format)
+	}
+
+	return slog.New(handler), closeFn, nil
+}
+
+func parseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	}
+	return slog.LevelInfo, fmt.Errorf("unknown log level: %v (expected debug, info, warn or error)", level)
+}
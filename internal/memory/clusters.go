@@ -0,0 +1,156 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func (s *Store) AddPostSighting(ctx context.Context, arg database.AddPostSightingParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.posts[arg.PostID]; !ok {
+		return errForeignKey("post_sightings_post_id_fkey")
+	}
+	if _, ok := s.feeds[arg.FeedID]; !ok {
+		return errForeignKey("post_sightings_feed_id_fkey")
+	}
+
+	key := postFeed{postID: arg.PostID, feedID: arg.FeedID}
+	if _, ok := s.sightings[key]; !ok {
+		s.sightings[key] = arg.SeenAt
+	}
+	return nil
+}
+
+// carries tells whether post is one of feedID's own or was sighted in it, like the feed_posts view, s.mu has to be held
+func (s *Store) carries(feedID uuid.UUID, post database.Post) bool {
+	if post.FeedID == feedID {
+		return true
+	}
+	_, sighted := s.sightings[postFeed{postID: post.ID, feedID: feedID}]
+	return sighted
+}
+
+func (s *Store) GetClusterCandidates(ctx context.Context, arg database.GetClusterCandidatesParams) ([]database.GetClusterCandidatesRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var posts []database.Post
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID || post.Simhash == 0 {
+			continue
+		}
+		if post.PublishedAt.Before(arg.Since) || !post.PublishedAt.Before(arg.Until) {
+			continue
+		}
+		posts = append(posts, post)
+	}
+	slices.SortFunc(posts, compareFetched)
+
+	rows := []database.GetClusterCandidatesRow{}
+	for _, post := range posts {
+		rows = append(rows, database.GetClusterCandidatesRow{
+			ID: post.ID,
+			ClusterID: post.ClusterID,
+			Simhash: post.Simhash,
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) SetPostCluster(ctx context.Context, arg database.SetPostClusterParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post, ok := s.posts[arg.ID]
+	if !ok {
+		return nil
+	}
+	post.ClusterID = arg.ClusterID
+	s.posts[post.ID] = post
+	return nil
+}
+
+func (s *Store) GetClusterFeedNames(ctx context.Context, arg database.GetClusterFeedNamesParams) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	inCluster := func(post database.Post) bool {
+		return post.ID == arg.PostID || (arg.ClusterID.Valid && post.ClusterID == arg.ClusterID)
+	}
+
+	feedIDs := make(map[uuid.UUID]bool)
+	for _, post := range s.posts {
+		if inCluster(post) {
+			feedIDs[post.FeedID] = true
+		}
+	}
+	for key := range s.sightings {
+		if post, ok := s.posts[key.postID]; ok && inCluster(post) {
+			feedIDs[key.feedID] = true
+		}
+	}
+	delete(feedIDs, arg.FeedID)
+
+	names := []string{}
+	for feedID := range feedIDs {
+		feed, ok := s.feeds[feedID]
+		if !ok {
+			continue
+		}
+		name := feed.Name
+		for _, follow := range s.follows {
+			if follow.FeedID == feedID && follow.UserID == arg.UserID && follow.DisplayName.Valid {
+				name = follow.DisplayName.String
+			}
+		}
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	return names, nil
+}
+
+func compareFetched(a, b database.Post) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareUUID(a.ID, b.ID))
+}
+
+// shownThroughOther tells whether the post of row is shown by another browsable row,
+// through its own feed or through a sighting feed with a lower id
+func shownThroughOther(browsable []database.GetPostsForUserRow, row database.GetPostsForUserRow) bool {
+	sighted := func(r database.GetPostsForUserRow) bool {
+		return r.BrowsedFeedID != r.FeedID
+	}
+	for _, other := range browsable {
+		if other.ID != row.ID {
+			continue
+		}
+		if !sighted(other) && sighted(row) {
+			return true
+		}
+		if sighted(other) == sighted(row) && compareUUID(other.BrowsedFeedID, row.BrowsedFeedID) < 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// shownEarlier tells whether the story of row is already shown by an earlier fetched browsable post of its cluster
+func shownEarlier(browsable []database.GetPostsForUserRow, row database.GetPostsForUserRow) bool {
+	if !row.ClusterID.Valid {
+		return false
+	}
+	for _, other := range browsable {
+		if other.ClusterID == row.ClusterID && cmp.Or(other.CreatedAt.Compare(row.CreatedAt), compareUUID(other.ID, row.ID)) < 0 {
+			return true
+		}
+	}
+	return false
+}
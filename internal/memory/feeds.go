@@ -0,0 +1,261 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func compareFeeds(a, b database.Feed) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareUUID(a.ID, b.ID))
+}
+
+func (s *Store) CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.feeds[arg.ID]; ok {
+		return database.Feed{}, errUnique("feeds_pkey")
+	}
+	for _, feed := range s.feeds {
+		if feed.Url == arg.Url {
+			return database.Feed{}, errUnique("feeds_url_key")
+		}
+	}
+	if arg.UserID.Valid {
+		if _, ok := s.users[arg.UserID.UUID]; !ok {
+			return database.Feed{}, errForeignKey("feeds_user_id_fkey")
+		}
+	}
+
+	feed := database.Feed{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Name: arg.Name,
+		Url: arg.Url,
+		UserID: arg.UserID,
+	}
+	s.feeds[feed.ID] = feed
+	return feed, nil
+}
+
+func (s *Store) DeleteFeed(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteFeed(id)
+	return nil
+}
+
+func (s *Store) DeleteFeeds(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.feeds {
+		s.deleteFeed(id)
+	}
+	return nil
+}
+
+func (s *Store) GetOwnedFeedIDs(ctx context.Context, userID uuid.NullUUID) ([]uuid.UUID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ids := []uuid.UUID{}
+	for id, feed := range s.feeds {
+		if userID.Valid && feed.UserID == userID {
+			ids = append(ids, id)
+		}
+	}
+	return ids, nil
+}
+
+func (s *Store) DeleteOrphanedFeeds(ctx context.Context, ids []uuid.UUID) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var deleted int64
+	for _, id := range ids {
+		feed, ok := s.feeds[id]
+		if !ok || feed.UserID.Valid || s.isFollowed(id) {
+			continue
+		}
+		s.deleteFeed(id)
+		deleted++
+	}
+	return deleted, nil
+}
+
+func (s *Store) isFollowed(feedID uuid.UUID) bool {
+	for _, follow := range s.follows {
+		if follow.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) GetAllFeeds(ctx context.Context) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return values(s.feeds, compareFeeds), nil
+}
+
+func (s *Store) GetFeedByURL(ctx context.Context, url string) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, feed := range s.feeds {
+		if feed.Url == url {
+			return feed, nil
+		}
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
+
+func (s *Store) GetFeedsForUser(ctx context.Context, userID uuid.UUID) ([]database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feeds := []database.Feed{}
+	for _, follow := range s.follows {
+		if follow.UserID == userID {
+			feeds = append(feeds, s.feeds[follow.FeedID])
+		}
+	}
+	slices.SortFunc(feeds, func(a, b database.Feed) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), compareFeeds(a, b))
+	})
+	return feeds, nil
+}
+
+// GetNextFeedToFetch picks the feed fetched longest ago, never fetched feeds come first
+func (s *Store) GetNextFeedToFetch(ctx context.Context) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feeds := values(s.feeds, func(a, b database.Feed) int {
+		switch {
+		case !a.LastFetchedAt.Valid && b.LastFetchedAt.Valid:
+			return -1
+		case a.LastFetchedAt.Valid && !b.LastFetchedAt.Valid:
+			return 1
+		case a.LastFetchedAt.Valid && b.LastFetchedAt.Valid:
+			if c := a.LastFetchedAt.Time.Compare(b.LastFetchedAt.Time); c != 0 {
+				return c
+			}
+		}
+		return compareFeeds(a, b)
+	})
+	if len(feeds) == 0 {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	return feeds[0], nil
+}
+
+func (s *Store) HandOffOwnedFeeds(ctx context.Context, arg database.HandOffOwnedFeedsParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var handedOff int64
+	for id, feed := range s.feeds {
+		if !feed.UserID.Valid || feed.UserID.UUID != arg.UserID {
+			continue
+		}
+
+		// the longest standing other follower takes the feed over
+		var next *database.FeedFollow
+		for _, follow := range s.follows {
+			if follow.FeedID != id || follow.UserID == arg.UserID {
+				continue
+			}
+			if next == nil || follow.CreatedAt.Before(next.CreatedAt) {
+				next = &follow
+			}
+		}
+
+		feed.UserID = uuid.NullUUID{}
+		if next != nil {
+			feed.UserID = uuid.NullUUID{UUID: next.UserID, Valid: true}
+		}
+		feed.UpdatedAt = arg.UpdatedAt
+		s.feeds[id] = feed
+		handedOff++
+	}
+	return handedOff, nil
+}
+
+func (s *Store) updateFeed(id uuid.UUID, update func(feed *database.Feed) error) (database.Feed, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	feed, ok := s.feeds[id]
+	if !ok {
+		return database.Feed{}, sql.ErrNoRows
+	}
+	if err := update(&feed); err != nil {
+		return database.Feed{}, err
+	}
+	s.feeds[id] = feed
+	return feed, nil
+}
+
+func (s *Store) MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) (database.Feed, error) {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) error {
+		feed.LastFetchedAt = arg.LastFetchedAt
+		feed.UpdatedAt = arg.UpdatedAt
+		return nil
+	})
+}
+
+func (s *Store) RenameFeed(ctx context.Context, arg database.RenameFeedParams) (database.Feed, error) {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) error {
+		feed.Name = arg.Name
+		feed.UpdatedAt = arg.UpdatedAt
+		return nil
+	})
+}
+
+func (s *Store) SetFeedOwner(ctx context.Context, arg database.SetFeedOwnerParams) (database.Feed, error) {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) error {
+		if arg.UserID.Valid {
+			if _, ok := s.users[arg.UserID.UUID]; !ok {
+				return errForeignKey("feeds_user_id_fkey")
+			}
+		}
+		feed.UserID = arg.UserID
+		feed.UpdatedAt = arg.UpdatedAt
+		return nil
+	})
+}
+
+func (s *Store) SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) (database.Feed, error) {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) error {
+		feed.RetentionKeepLast = arg.RetentionKeepLast
+		feed.RetentionMaxAgeSeconds = arg.RetentionMaxAgeSeconds
+		feed.RetentionKeepUnread = arg.RetentionKeepUnread
+		feed.UpdatedAt = arg.UpdatedAt
+		return nil
+	})
+}
+
+func (s *Store) SetFeedURL(ctx context.Context, arg database.SetFeedURLParams) (database.Feed, error) {
+	return s.updateFeed(arg.ID, func(feed *database.Feed) error {
+		for _, other := range s.feeds {
+			if other.ID != feed.ID && other.Url == arg.Url {
+				return errUnique("feeds_url_key")
+			}
+		}
+		feed.Url = arg.Url
+		feed.UpdatedAt = arg.UpdatedAt
+		return nil
+	})
+}
@@ -0,0 +1,341 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func compareFollows(a, b database.FeedFollow) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareUUID(a.ID, b.ID))
+}
+
+func (s *Store) CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.follows[arg.ID]; ok {
+		return database.CreateFeedFollowRow{}, errUnique("feed_follows_pkey")
+	}
+	for _, follow := range s.follows {
+		if follow.UserID == arg.UserID && follow.FeedID == arg.FeedID {
+			return database.CreateFeedFollowRow{}, errUnique("feed_follows_user_id_feed_id_key")
+		}
+	}
+	user, ok := s.users[arg.UserID]
+	if !ok {
+		return database.CreateFeedFollowRow{}, errForeignKey("feed_follows_user_id_fkey")
+	}
+	feed, ok := s.feeds[arg.FeedID]
+	if !ok {
+		return database.CreateFeedFollowRow{}, errForeignKey("feed_follows_feed_id_fkey")
+	}
+
+	follow := database.FeedFollow{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID: arg.UserID,
+		FeedID: arg.FeedID,
+	}
+	s.follows[follow.ID] = follow
+
+	return database.CreateFeedFollowRow{
+		ID: follow.ID,
+		CreatedAt: follow.CreatedAt,
+		UpdatedAt: follow.UpdatedAt,
+		UserID: follow.UserID,
+		FeedID: follow.FeedID,
+		FeedName: feed.Name,
+		UserName: user.Name,
+	}, nil
+}
+
+func (s *Store) DeleteFeedFollowByUrl(ctx context.Context, arg database.DeleteFeedFollowByUrlParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id, follow := range s.follows {
+		if follow.UserID == arg.UserID && s.feeds[follow.FeedID].Url == arg.Url {
+			s.deleteFollow(id)
+		}
+	}
+	return nil
+}
+
+func (s *Store) DeleteFeedFollows(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for id := range s.follows {
+		s.deleteFollow(id)
+	}
+	return nil
+}
+
+func (s *Store) GetFeedFollowByURL(ctx context.Context, arg database.GetFeedFollowByURLParams) (database.FeedFollow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, follow := range s.follows {
+		if follow.UserID == arg.UserID && s.feeds[follow.FeedID].Url == arg.Url {
+			return follow, nil
+		}
+	}
+	return database.FeedFollow{}, sql.ErrNoRows
+}
+
+// unreadCount counts the posts feedID carries which userID hasn't read, s.mu has to be held
+func (s *Store) unreadCount(userID, feedID uuid.UUID) int64 {
+	var unread int64
+	for _, post := range s.posts {
+		if !s.carries(feedID, post) {
+			continue
+		}
+		if _, read := s.reads[userPost{userID: userID, postID: post.ID}]; !read {
+			unread++
+		}
+	}
+	return unread
+}
+
+func (s *Store) GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetFeedFollowsForUserRow{}
+	for _, follow := range values(s.follows, compareFollows) {
+		if follow.UserID != userID {
+			continue
+		}
+
+		feed := s.feeds[follow.FeedID]
+		feedName := feed.Name
+		if follow.DisplayName.Valid {
+			feedName = follow.DisplayName.String
+		}
+
+		rows = append(rows, database.GetFeedFollowsForUserRow{
+			ID: follow.ID,
+			CreatedAt: follow.CreatedAt,
+			UpdatedAt: follow.UpdatedAt,
+			UserID: follow.UserID,
+			FeedID: follow.FeedID,
+			DisplayName: follow.DisplayName,
+			Notes: follow.Notes,
+			FeedName: feedName,
+			OriginalName: feed.Name,
+			FeedUrl: feed.Url,
+			UserName: s.users[follow.UserID].Name,
+			UnreadCount: s.unreadCount(follow.UserID, follow.FeedID),
+		})
+	}
+	return rows, nil
+}
+
+func (s *Store) updateFollow(id uuid.UUID, update func(follow *database.FeedFollow)) (database.FeedFollow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	follow, ok := s.follows[id]
+	if !ok {
+		return database.FeedFollow{}, sql.ErrNoRows
+	}
+	update(&follow)
+	s.follows[id] = follow
+	return follow, nil
+}
+
+func (s *Store) SetFeedFollowName(ctx context.Context, arg database.SetFeedFollowNameParams) (database.FeedFollow, error) {
+	return s.updateFollow(arg.ID, func(follow *database.FeedFollow) {
+		follow.DisplayName = arg.DisplayName
+		follow.UpdatedAt = arg.UpdatedAt
+	})
+}
+
+func (s *Store) SetFeedFollowNotes(ctx context.Context, arg database.SetFeedFollowNotesParams) (database.FeedFollow, error) {
+	return s.updateFollow(arg.ID, func(follow *database.FeedFollow) {
+		follow.Notes = arg.Notes
+		follow.UpdatedAt = arg.UpdatedAt
+	})
+}
+
+func (s *Store) AddFollowToFolder(ctx context.Context, arg database.AddFollowToFolderParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.follows[arg.FeedFollowID]; !ok {
+		return errForeignKey("feed_follow_folders_feed_follow_id_fkey")
+	}
+	if _, ok := s.folders[arg.FolderID]; !ok {
+		return errForeignKey("feed_follow_folders_folder_id_fkey")
+	}
+
+	s.followFolders[followFolder{followID: arg.FeedFollowID, folderID: arg.FolderID}] = true
+	return nil
+}
+
+func (s *Store) CreateFolder(ctx context.Context, arg database.CreateFolderParams) (database.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.folders[arg.ID]; ok {
+		return database.Folder{}, errUnique("folders_pkey")
+	}
+	for _, folder := range s.folders {
+		if folder.UserID == arg.UserID && folder.Name == arg.Name {
+			return database.Folder{}, errUnique("folders_user_id_name_key")
+		}
+	}
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Folder{}, errForeignKey("folders_user_id_fkey")
+	}
+
+	folder := database.Folder{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID: arg.UserID,
+		Name: arg.Name,
+	}
+	s.folders[folder.ID] = folder
+	return folder, nil
+}
+
+func (s *Store) DeleteFolder(ctx context.Context, id uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.deleteFolder(id)
+	return nil
+}
+
+func (s *Store) GetFolderByName(ctx context.Context, arg database.GetFolderByNameParams) (database.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, folder := range s.folders {
+		if folder.UserID == arg.UserID && folder.Name == arg.Name {
+			return folder, nil
+		}
+	}
+	return database.Folder{}, sql.ErrNoRows
+}
+
+func (s *Store) GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]database.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folders := []database.Folder{}
+	for _, folder := range s.folders {
+		if folder.UserID == userID {
+			folders = append(folders, folder)
+		}
+	}
+	slices.SortFunc(folders, func(a, b database.Folder) int {
+		return cmp.Or(strings.Compare(a.Name, b.Name), compareUUID(a.ID, b.ID))
+	})
+	return folders, nil
+}
+
+func (s *Store) GetFollowFoldersForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFollowFoldersForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetFollowFoldersForUserRow{}
+	for key := range s.followFolders {
+		folder := s.folders[key.folderID]
+		if folder.UserID != userID {
+			continue
+		}
+		rows = append(rows, database.GetFollowFoldersForUserRow{
+			FeedFollowID: key.followID,
+			FolderID: folder.ID,
+			FolderName: folder.Name,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetFollowFoldersForUserRow) int {
+		return cmp.Or(strings.Compare(a.FolderName, b.FolderName), compareUUID(a.FeedFollowID, b.FeedFollowID))
+	})
+	return rows, nil
+}
+
+func (s *Store) RemoveFollowFromAllFolders(ctx context.Context, feedFollowID uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for key := range s.followFolders {
+		if key.followID == feedFollowID {
+			delete(s.followFolders, key)
+		}
+	}
+	return nil
+}
+
+func (s *Store) RemoveFollowFromFolder(ctx context.Context, arg database.RemoveFollowFromFolderParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := followFolder{followID: arg.FeedFollowID, folderID: arg.FolderID}
+	if !s.followFolders[key] {
+		return 0, nil
+	}
+	delete(s.followFolders, key)
+	return 1, nil
+}
+
+func (s *Store) RenameFolder(ctx context.Context, arg database.RenameFolderParams) (database.Folder, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	folder, ok := s.folders[arg.ID]
+	if !ok {
+		return database.Folder{}, sql.ErrNoRows
+	}
+	for _, other := range s.folders {
+		if other.ID != folder.ID && other.UserID == folder.UserID && other.Name == arg.Name {
+			return database.Folder{}, errUnique("folders_user_id_name_key")
+		}
+	}
+
+	folder.Name = arg.Name
+	folder.UpdatedAt = arg.UpdatedAt
+	s.folders[folder.ID] = folder
+	return folder, nil
+}
+
+// isFollowing tells whether userID follows feedID, s.mu has to be held
+func (s *Store) isFollowing(userID, feedID uuid.UUID) bool {
+	for _, follow := range s.follows {
+		if follow.UserID == userID && follow.FeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// followsPost tells whether userID follows a feed carrying post, s.mu has to be held
+func (s *Store) followsPost(userID uuid.UUID, post database.Post) bool {
+	for _, follow := range s.follows {
+		if follow.UserID == userID && s.carries(follow.FeedID, post) {
+			return true
+		}
+	}
+	return false
+}
+
+// postInFolder tells whether a follow of a feed carrying post was put into folderID, s.mu has to be held
+func (s *Store) postInFolder(folderID uuid.UUID, post database.Post) bool {
+	for key := range s.followFolders {
+		if key.folderID == folderID && s.carries(s.follows[key.followID].FeedID, post) {
+			return true
+		}
+	}
+	return false
+}
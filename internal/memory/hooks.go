@@ -0,0 +1,166 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func compareHooks(a, b database.Hook) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareUUID(a.ID, b.ID))
+}
+
+func (s *Store) CreateHook(ctx context.Context, arg database.CreateHookParams) (database.Hook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.hooks[arg.ID]; ok {
+		return database.Hook{}, errUnique("hooks_pkey")
+	}
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Hook{}, errForeignKey("hooks_user_id_fkey")
+	}
+	if arg.FeedID.Valid {
+		if _, ok := s.feeds[arg.FeedID.UUID]; !ok {
+			return database.Hook{}, errForeignKey("hooks_feed_id_fkey")
+		}
+	}
+
+	hook := database.Hook{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		UserID: arg.UserID,
+		FeedID: arg.FeedID,
+		Kind: arg.Kind,
+		Target: arg.Target,
+		Template: arg.Template,
+		MaxAttempts: arg.MaxAttempts,
+	}
+	s.hooks[hook.ID] = hook
+	return hook, nil
+}
+
+func (s *Store) CreateHookDelivery(ctx context.Context, arg database.CreateHookDeliveryParams) (database.HookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.deliveries[arg.ID]; ok {
+		return database.HookDelivery{}, errUnique("hook_deliveries_pkey")
+	}
+	if _, ok := s.hooks[arg.HookID]; !ok {
+		return database.HookDelivery{}, errForeignKey("hook_deliveries_hook_id_fkey")
+	}
+	if _, ok := s.posts[arg.PostID]; !ok {
+		return database.HookDelivery{}, errForeignKey("hook_deliveries_post_id_fkey")
+	}
+
+	delivery := database.HookDelivery{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		HookID: arg.HookID,
+		PostID: arg.PostID,
+		Attempts: arg.Attempts,
+		Succeeded: arg.Succeeded,
+		Error: arg.Error,
+	}
+	s.deliveries[delivery.ID] = delivery
+	return delivery, nil
+}
+
+func (s *Store) DeleteHook(ctx context.Context, arg database.DeleteHookParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hook, ok := s.hooks[arg.ID]
+	if !ok || hook.UserID != arg.UserID {
+		return 0, nil
+	}
+	s.deleteHook(hook.ID)
+	return 1, nil
+}
+
+func (s *Store) GetHookDeliveriesForUser(ctx context.Context, arg database.GetHookDeliveriesForUserParams) ([]database.GetHookDeliveriesForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetHookDeliveriesForUserRow{}
+	for _, delivery := range s.deliveries {
+		hook := s.hooks[delivery.HookID]
+		if hook.UserID != arg.UserID {
+			continue
+		}
+		rows = append(rows, database.GetHookDeliveriesForUserRow{
+			ID: delivery.ID,
+			CreatedAt: delivery.CreatedAt,
+			HookID: delivery.HookID,
+			PostID: delivery.PostID,
+			Attempts: delivery.Attempts,
+			Succeeded: delivery.Succeeded,
+			Error: delivery.Error,
+			HookKind: hook.Kind,
+			HookTarget: hook.Target,
+			PostTitle: s.posts[delivery.PostID].Title,
+		})
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetHookDeliveriesForUserRow) int {
+		return cmp.Or(b.CreatedAt.Compare(a.CreatedAt), compareUUID(a.ID, b.ID))
+	})
+	if len(rows) > int(arg.Limit) {
+		rows = rows[:max(arg.Limit, 0)]
+	}
+	return rows, nil
+}
+
+// GetHooksForFeed returns the hooks of feedID and the hooks without a feed of its followers
+func (s *Store) GetHooksForFeed(ctx context.Context, feedID uuid.UUID) ([]database.Hook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	hooks := []database.Hook{}
+	for _, hook := range values(s.hooks, compareHooks) {
+		if hook.FeedID.Valid && hook.FeedID.UUID == feedID {
+			hooks = append(hooks, hook)
+			continue
+		}
+		if !hook.FeedID.Valid && s.isFollowing(hook.UserID, feedID) {
+			hooks = append(hooks, hook)
+		}
+	}
+	return hooks, nil
+}
+
+func (s *Store) GetHooksForUser(ctx context.Context, userID uuid.UUID) ([]database.GetHooksForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetHooksForUserRow{}
+	for _, hook := range values(s.hooks, compareHooks) {
+		if hook.UserID != userID {
+			continue
+		}
+
+		row := database.GetHooksForUserRow{
+			ID: hook.ID,
+			CreatedAt: hook.CreatedAt,
+			UpdatedAt: hook.UpdatedAt,
+			UserID: hook.UserID,
+			FeedID: hook.FeedID,
+			Kind: hook.Kind,
+			Target: hook.Target,
+			Template: hook.Template,
+			MaxAttempts: hook.MaxAttempts,
+		}
+		if feed, ok := s.feeds[hook.FeedID.UUID]; hook.FeedID.Valid && ok {
+			row.FeedName.String = feed.Name
+			row.FeedName.Valid = true
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
@@ -0,0 +1,235 @@
+// Package memory keeps everything gator stores in maps, nothing survives the process.
+// It behaves like the SQL schema does: names and urls are unique, rows need the rows they
+// point at and deleting a row takes everything hanging off it along.
+package memory
+
+import (
+	"bytes"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+type userPost struct {
+	userID	uuid.UUID
+	postID	uuid.UUID
+}
+
+type followFolder struct {
+	followID	uuid.UUID
+	folderID	uuid.UUID
+}
+
+type postFeed struct {
+	postID	uuid.UUID
+	feedID	uuid.UUID
+}
+
+type Store struct {
+	mu				sync.Mutex
+	users			map[uuid.UUID]database.User
+	feeds			map[uuid.UUID]database.Feed
+	follows			map[uuid.UUID]database.FeedFollow
+	folders			map[uuid.UUID]database.Folder
+	followFolders	map[followFolder]bool
+	posts			map[uuid.UUID]database.Post
+	revisions		map[uuid.UUID]database.PostRevision
+	sightings		map[postFeed]time.Time
+	reads			map[userPost]time.Time
+	stars			map[userPost]time.Time
+	readLater		map[userPost]database.ReadLater
+	hooks			map[uuid.UUID]database.Hook
+	deliveries		map[uuid.UUID]database.HookDelivery
+	mutes			map[uuid.UUID]database.Mute
+}
+
+func New() *Store {
+	return &Store{
+		users: make(map[uuid.UUID]database.User),
+		feeds: make(map[uuid.UUID]database.Feed),
+		follows: make(map[uuid.UUID]database.FeedFollow),
+		folders: make(map[uuid.UUID]database.Folder),
+		followFolders: make(map[followFolder]bool),
+		posts: make(map[uuid.UUID]database.Post),
+		revisions: make(map[uuid.UUID]database.PostRevision),
+		sightings: make(map[postFeed]time.Time),
+		reads: make(map[userPost]time.Time),
+		stars: make(map[userPost]time.Time),
+		readLater: make(map[userPost]database.ReadLater),
+		hooks: make(map[uuid.UUID]database.Hook),
+		deliveries: make(map[uuid.UUID]database.HookDelivery),
+		mutes: make(map[uuid.UUID]database.Mute),
+	}
+}
+
+func errUnique(constraint string) error {
+	return fmt.Errorf("duplicate key value violates unique constraint %q", constraint)
+}
+
+func errForeignKey(constraint string) error {
+	return fmt.Errorf("insert or update violates foreign key constraint %q", constraint)
+}
+
+func compareUUID(a, b uuid.UUID) int {
+	return bytes.Compare(a[:], b[:])
+}
+
+// values returns the rows of m ordered by cmpFn
+func values[K comparable, V any](m map[K]V, cmpFn func(a, b V) int) []V {
+	rows := make([]V, 0, len(m))
+	for _, row := range m {
+		rows = append(rows, row)
+	}
+	slices.SortFunc(rows, cmpFn)
+	return rows
+}
+
+// the delete helpers below cascade the way the foreign keys of the schema do, s.mu has to be held
+
+func (s *Store) deleteUser(id uuid.UUID) {
+	delete(s.users, id)
+
+	for followID, follow := range s.follows {
+		if follow.UserID == id {
+			s.deleteFollow(followID)
+		}
+	}
+	for folderID, folder := range s.folders {
+		if folder.UserID == id {
+			s.deleteFolder(folderID)
+		}
+	}
+	for hookID, hook := range s.hooks {
+		if hook.UserID == id {
+			s.deleteHook(hookID)
+		}
+	}
+	for muteID, mute := range s.mutes {
+		if mute.UserID == id {
+			delete(s.mutes, muteID)
+		}
+	}
+	for key := range s.reads {
+		if key.userID == id {
+			delete(s.reads, key)
+		}
+	}
+	for key := range s.stars {
+		if key.userID == id {
+			delete(s.stars, key)
+		}
+	}
+	for key := range s.readLater {
+		if key.userID == id {
+			delete(s.readLater, key)
+		}
+	}
+
+	// feeds outlive their owner
+	for feedID, feed := range s.feeds {
+		if feed.UserID.Valid && feed.UserID.UUID == id {
+			feed.UserID = uuid.NullUUID{}
+			s.feeds[feedID] = feed
+		}
+	}
+}
+
+func (s *Store) deleteFeed(id uuid.UUID) {
+	delete(s.feeds, id)
+
+	for followID, follow := range s.follows {
+		if follow.FeedID == id {
+			s.deleteFollow(followID)
+		}
+	}
+	for postID, post := range s.posts {
+		if post.FeedID == id {
+			s.deletePost(postID)
+		}
+	}
+	for hookID, hook := range s.hooks {
+		if hook.FeedID.Valid && hook.FeedID.UUID == id {
+			s.deleteHook(hookID)
+		}
+	}
+	for key := range s.sightings {
+		if key.feedID == id {
+			delete(s.sightings, key)
+		}
+	}
+	for muteID, mute := range s.mutes {
+		if mute.FeedID.Valid && mute.FeedID.UUID == id {
+			delete(s.mutes, muteID)
+		}
+	}
+}
+
+func (s *Store) deleteFollow(id uuid.UUID) {
+	delete(s.follows, id)
+
+	for key := range s.followFolders {
+		if key.followID == id {
+			delete(s.followFolders, key)
+		}
+	}
+}
+
+func (s *Store) deleteFolder(id uuid.UUID) {
+	delete(s.folders, id)
+
+	for key := range s.followFolders {
+		if key.folderID == id {
+			delete(s.followFolders, key)
+		}
+	}
+}
+
+func (s *Store) deletePost(id uuid.UUID) {
+	delete(s.posts, id)
+
+	for revisionID, revision := range s.revisions {
+		if revision.PostID == id {
+			delete(s.revisions, revisionID)
+		}
+	}
+	for key := range s.sightings {
+		if key.postID == id {
+			delete(s.sightings, key)
+		}
+	}
+	for key := range s.reads {
+		if key.postID == id {
+			delete(s.reads, key)
+		}
+	}
+	for key := range s.stars {
+		if key.postID == id {
+			delete(s.stars, key)
+		}
+	}
+	for key := range s.readLater {
+		if key.postID == id {
+			delete(s.readLater, key)
+		}
+	}
+	for deliveryID, delivery := range s.deliveries {
+		if delivery.PostID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+}
+
+func (s *Store) deleteHook(id uuid.UUID) {
+	delete(s.hooks, id)
+
+	for deliveryID, delivery := range s.deliveries {
+		if delivery.HookID == id {
+			delete(s.deliveries, deliveryID)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func compareMutes(a, b database.Mute) int {
+	return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), compareUUID(a.ID, b.ID))
+}
+
+func (s *Store) CreateMute(ctx context.Context, arg database.CreateMuteParams) (database.Mute, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.mutes[arg.ID]; ok {
+		return database.Mute{}, errUnique("mutes_pkey")
+	}
+	if _, ok := s.users[arg.UserID]; !ok {
+		return database.Mute{}, errForeignKey("mutes_user_id_fkey")
+	}
+	if arg.FeedID.Valid {
+		if _, ok := s.feeds[arg.FeedID.UUID]; !ok {
+			return database.Mute{}, errForeignKey("mutes_feed_id_fkey")
+		}
+	}
+
+	mute := database.Mute{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UserID: arg.UserID,
+		FeedID: arg.FeedID,
+		Kind: arg.Kind,
+		Pattern: arg.Pattern,
+	}
+	s.mutes[mute.ID] = mute
+	return mute, nil
+}
+
+func (s *Store) DeleteMute(ctx context.Context, arg database.DeleteMuteParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	mute, ok := s.mutes[arg.ID]
+	if !ok || mute.UserID != arg.UserID {
+		return 0, nil
+	}
+	delete(s.mutes, mute.ID)
+	return 1, nil
+}
+
+func (s *Store) GetMutesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetMutesForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetMutesForUserRow{}
+	for _, mute := range values(s.mutes, compareMutes) {
+		if mute.UserID != userID {
+			continue
+		}
+
+		var feedName sql.NullString
+		if feed, ok := s.feeds[mute.FeedID.UUID]; ok && mute.FeedID.Valid {
+			feedName = sql.NullString{String: feed.Name, Valid: true}
+		}
+		rows = append(rows, database.GetMutesForUserRow{
+			ID: mute.ID,
+			CreatedAt: mute.CreatedAt,
+			UserID: mute.UserID,
+			FeedID: mute.FeedID,
+			Kind: mute.Kind,
+			Pattern: mute.Pattern,
+			FeedName: feedName,
+		})
+	}
+	return rows, nil
+}
@@ -0,0 +1,638 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+func comparePublished(a, b database.Post) int {
+	return cmp.Or(a.PublishedAt.Compare(b.PublishedAt), compareUUID(a.ID, b.ID))
+}
+
+func (s *Store) CreatePost(ctx context.Context, arg database.CreatePostParams) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.posts[arg.ID]; ok {
+		return database.Post{}, errUnique("posts_pkey")
+	}
+	for _, post := range s.posts {
+		if post.Url == arg.Url {
+			return database.Post{}, errUnique("posts_url_key")
+		}
+		if arg.Guid.Valid && post.FeedID == arg.FeedID && post.Guid == arg.Guid {
+			return database.Post{}, errUnique("posts_feed_id_guid_idx")
+		}
+	}
+	if _, ok := s.feeds[arg.FeedID]; !ok {
+		return database.Post{}, errForeignKey("posts_feed_id_fkey")
+	}
+
+	post := database.Post{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		UpdatedAt: arg.UpdatedAt,
+		Title: arg.Title,
+		Url: arg.Url,
+		Description: arg.Description,
+		PublishedAt: arg.PublishedAt,
+		FeedID: arg.FeedID,
+		Guid: arg.Guid,
+		Content: arg.Content,
+		ContentHash: arg.ContentHash,
+		Simhash: arg.Simhash,
+		Author: arg.Author,
+		Categories: arg.Categories,
+	}
+	s.posts[post.ID] = post
+	return post, nil
+}
+
+func (s *Store) DeletePostsByIDs(ctx context.Context, ids []uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, id := range ids {
+		s.deletePost(id)
+	}
+	return nil
+}
+
+func (s *Store) GetPostByFeedGUID(ctx context.Context, arg database.GetPostByFeedGUIDParams) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !arg.Guid.Valid {
+		return database.Post{}, sql.ErrNoRows
+	}
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID && post.Guid == arg.Guid {
+			return post, nil
+		}
+	}
+	return database.Post{}, sql.ErrNoRows
+}
+
+func (s *Store) GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post, ok := s.posts[id]
+	if !ok {
+		return database.Post{}, sql.ErrNoRows
+	}
+	return post, nil
+}
+
+func (s *Store) GetPostByURL(ctx context.Context, url string) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, post := range s.posts {
+		if post.Url == url {
+			return post, nil
+		}
+	}
+	return database.Post{}, sql.ErrNoRows
+}
+
+func (s *Store) GetLatestPostForFeed(ctx context.Context, feedID uuid.UUID) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest database.Post
+	found := false
+	for _, post := range s.posts {
+		if post.FeedID == feedID && (!found || comparePublished(post, latest) > 0) {
+			latest = post
+			found = true
+		}
+	}
+	if !found {
+		return database.Post{}, sql.ErrNoRows
+	}
+	return latest, nil
+}
+
+func (s *Store) UpdatePostContent(ctx context.Context, arg database.UpdatePostContentParams) (database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	post, ok := s.posts[arg.ID]
+	if !ok {
+		return database.Post{}, sql.ErrNoRows
+	}
+	for _, other := range s.posts {
+		if other.ID == post.ID {
+			continue
+		}
+		if other.Url == arg.Url {
+			return database.Post{}, errUnique("posts_url_key")
+		}
+		if arg.Guid.Valid && other.FeedID == post.FeedID && other.Guid == arg.Guid {
+			return database.Post{}, errUnique("posts_feed_id_guid_idx")
+		}
+	}
+
+	post.Title = arg.Title
+	post.Description = arg.Description
+	post.Content = arg.Content
+	post.ContentHash = arg.ContentHash
+	post.Guid = arg.Guid
+	post.Url = arg.Url
+	post.UpdatedAt = arg.UpdatedAt
+	if arg.RevisedAt.Valid {
+		post.RevisedAt = arg.RevisedAt
+	}
+	s.posts[post.ID] = post
+	return post, nil
+}
+
+// ilike matches value against a LIKE pattern ignoring case, \ escapes % and _
+func ilike(value, pattern string) bool {
+	v := []rune(strings.ToLower(value))
+	p := []rune(strings.ToLower(pattern))
+
+	var match func(vi, pi int) bool
+	match = func(vi, pi int) bool {
+		for pi < len(p) {
+			switch p[pi] {
+			case '%':
+				for i := vi; i <= len(v); i++ {
+					if match(i, pi+1) {
+						return true
+					}
+				}
+				return false
+			case '_':
+				if vi >= len(v) {
+					return false
+				}
+			case '\\':
+				if pi+1 < len(p) {
+					pi++
+				}
+				fallthrough
+			default:
+				if vi >= len(v) || v[vi] != p[pi] {
+					return false
+				}
+			}
+			vi++
+			pi++
+		}
+		return vi == len(v)
+	}
+	return match(0, 0)
+}
+
+// GetPostsForUser pages through the posts of the feeds the user follows like the SQL version does,
+// the browsable rows pass the filters and of them each post and each story is shown once
+func (s *Store) GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	sortTime := func(row database.GetPostsForUserRow) time.Time {
+		if arg.SortByFetched {
+			return row.CreatedAt
+		}
+		return row.PublishedAt
+	}
+	// compare orders the page, newest first unless reverse is set
+	compare := func(aTime time.Time, aID uuid.UUID, bTime time.Time, bID uuid.UUID) int {
+		c := cmp.Or(aTime.Compare(bTime), compareUUID(aID, bID))
+		if arg.Reverse {
+			return c
+		}
+		return -c
+	}
+
+	browsable := s.browsablePosts(arg)
+	rows := []database.GetPostsForUserRow{}
+	for _, row := range browsable {
+		if shownThroughOther(browsable, row) || shownEarlier(browsable, row) {
+			continue
+		}
+		if arg.CursorTime.Valid && compare(sortTime(row), row.ID, arg.CursorTime.Time, arg.CursorID) <= 0 {
+			continue
+		}
+		rows = append(rows, row)
+	}
+
+	slices.SortFunc(rows, func(a, b database.GetPostsForUserRow) int {
+		return compare(sortTime(a), a.ID, sortTime(b), b.ID)
+	})
+	if len(rows) > int(arg.Limit) {
+		rows = rows[:max(arg.Limit, 0)]
+	}
+	return rows, nil
+}
+
+// browsablePosts lists every followed feed with each post it carries which passes the filters of arg, s.mu has to be held
+func (s *Store) browsablePosts(arg database.GetPostsForUserParams) []database.GetPostsForUserRow {
+	var rows []database.GetPostsForUserRow
+	for _, follow := range s.follows {
+		if follow.UserID != arg.UserID {
+			continue
+		}
+		if arg.FeedID.Valid && follow.FeedID != arg.FeedID.UUID {
+			continue
+		}
+		if arg.FolderID.Valid && !s.followFolders[followFolder{followID: follow.ID, folderID: arg.FolderID.UUID}] {
+			continue
+		}
+
+		feedName := s.feeds[follow.FeedID].Name
+		if follow.DisplayName.Valid {
+			feedName = follow.DisplayName.String
+		}
+
+		for _, post := range s.posts {
+			if !s.carries(follow.FeedID, post) {
+				continue
+			}
+			_, isRead := s.reads[userPost{userID: arg.UserID, postID: post.ID}]
+			if arg.UnreadOnly && isRead {
+				continue
+			}
+			if arg.Since.Valid && post.PublishedAt.Before(arg.Since.Time) {
+				continue
+			}
+			if arg.Until.Valid && !post.PublishedAt.Before(arg.Until.Time) {
+				continue
+			}
+			if arg.Title.Valid && !ilike(post.Title, "%"+arg.Title.String+"%") {
+				continue
+			}
+
+			rows = append(rows, database.GetPostsForUserRow{
+				ID: post.ID,
+				CreatedAt: post.CreatedAt,
+				UpdatedAt: post.UpdatedAt,
+				Title: post.Title,
+				Url: post.Url,
+				Description: post.Description,
+				PublishedAt: post.PublishedAt,
+				FeedID: post.FeedID,
+				Guid: post.Guid,
+				Content: post.Content,
+				ContentHash: post.ContentHash,
+				RevisedAt: post.RevisedAt,
+				Simhash: post.Simhash,
+				ClusterID: post.ClusterID,
+				Author: post.Author,
+				Categories: post.Categories,
+				IsRead: isRead,
+				FeedName: feedName,
+				BrowsedFeedID: follow.FeedID,
+			})
+		}
+	}
+	return rows
+}
+
+// GetPrunablePosts follows the rules of the SQL version: posts beyond keep_last or older than
+// older_than, unless keep_unread protects them or someone starred or queued them
+func (s *Store) GetPrunablePosts(ctx context.Context, arg database.GetPrunablePostsParams) ([]database.Post, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var posts []database.Post
+	for _, post := range s.posts {
+		if post.FeedID == arg.FeedID {
+			posts = append(posts, post)
+		}
+	}
+	slices.SortFunc(posts, func(a, b database.Post) int {
+		return -comparePublished(a, b)
+	})
+
+	prunable := []database.Post{}
+	for i, post := range posts {
+		tooMany := arg.KeepLast.Valid && i >= int(arg.KeepLast.Int32)
+		tooOld := arg.OlderThan.Valid && post.PublishedAt.Before(arg.OlderThan.Time)
+		if !tooMany && !tooOld {
+			continue
+		}
+		if arg.KeepUnread && s.unreadBySomeone(post) {
+			continue
+		}
+		if s.isSaved(post.ID) {
+			continue
+		}
+		prunable = append(prunable, post)
+	}
+
+	slices.SortFunc(prunable, comparePublished)
+	return prunable, nil
+}
+
+// unreadBySomeone tells whether a follower of the post's feed hasn't read it, s.mu has to be held
+func (s *Store) unreadBySomeone(post database.Post) bool {
+	for _, follow := range s.follows {
+		if follow.FeedID != post.FeedID {
+			continue
+		}
+		if _, read := s.reads[userPost{userID: follow.UserID, postID: post.ID}]; !read {
+			return true
+		}
+	}
+	return false
+}
+
+// isSaved tells whether anyone starred or queued the post, s.mu has to be held
+func (s *Store) isSaved(postID uuid.UUID) bool {
+	for key := range s.stars {
+		if key.postID == postID {
+			return true
+		}
+	}
+	for key := range s.readLater {
+		if key.postID == postID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) CreatePostRevision(ctx context.Context, arg database.CreatePostRevisionParams) (database.PostRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.revisions[arg.ID]; ok {
+		return database.PostRevision{}, errUnique("post_revisions_pkey")
+	}
+	if _, ok := s.posts[arg.PostID]; !ok {
+		return database.PostRevision{}, errForeignKey("post_revisions_post_id_fkey")
+	}
+
+	revision := database.PostRevision{
+		ID: arg.ID,
+		CreatedAt: arg.CreatedAt,
+		PostID: arg.PostID,
+		Title: arg.Title,
+		Description: arg.Description,
+		Content: arg.Content,
+		ContentHash: arg.ContentHash,
+	}
+	s.revisions[revision.ID] = revision
+	return revision, nil
+}
+
+func (s *Store) GetLatestPostRevision(ctx context.Context, postID uuid.UUID) (database.PostRevision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var latest *database.PostRevision
+	for _, revision := range s.revisions {
+		if revision.PostID != postID {
+			continue
+		}
+		if latest == nil || revision.CreatedAt.After(latest.CreatedAt) {
+			latest = &revision
+		}
+	}
+	if latest == nil {
+		return database.PostRevision{}, sql.ErrNoRows
+	}
+	return *latest, nil
+}
+
+// checkUserPost makes sure both rows a read, star or queue entry points at exist, s.mu has to be held
+func (s *Store) checkUserPost(table string, key userPost) error {
+	if _, ok := s.users[key.userID]; !ok {
+		return errForeignKey(table + "_user_id_fkey")
+	}
+	if _, ok := s.posts[key.postID]; !ok {
+		return errForeignKey(table + "_post_id_fkey")
+	}
+	return nil
+}
+
+func (s *Store) MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userPost{userID: arg.UserID, postID: arg.PostID}
+	if err := s.checkUserPost("post_reads", key); err != nil {
+		return err
+	}
+	if _, ok := s.reads[key]; !ok {
+		s.reads[key] = arg.ReadAt
+	}
+	return nil
+}
+
+func (s *Store) MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.reads, userPost{userID: arg.UserID, postID: arg.PostID})
+	return nil
+}
+
+func (s *Store) MarkAllPostsRead(ctx context.Context, arg database.MarkAllPostsReadParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var marked int64
+	for _, follow := range s.follows {
+		if follow.UserID != arg.UserID || (arg.FeedID.Valid && follow.FeedID != arg.FeedID.UUID) {
+			continue
+		}
+		for _, post := range s.posts {
+			if !s.carries(follow.FeedID, post) {
+				continue
+			}
+			key := userPost{userID: arg.UserID, postID: post.ID}
+			if _, ok := s.reads[key]; ok {
+				continue
+			}
+			s.reads[key] = arg.ReadAt
+			marked++
+		}
+	}
+	return marked, nil
+}
+
+func (s *Store) GetReadPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetReadPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetReadPostsForUserRow{}
+	for key, readAt := range s.reads {
+		if key.userID != userID {
+			continue
+		}
+		post := s.posts[key.postID]
+		rows = append(rows, database.GetReadPostsForUserRow{
+			ID: post.ID,
+			CreatedAt: post.CreatedAt,
+			UpdatedAt: post.UpdatedAt,
+			Title: post.Title,
+			Url: post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID: post.FeedID,
+			Guid: post.Guid,
+			Content: post.Content,
+			ContentHash: post.ContentHash,
+			RevisedAt: post.RevisedAt,
+			Simhash: post.Simhash,
+			ClusterID: post.ClusterID,
+			Author: post.Author,
+			Categories: post.Categories,
+			ReadAt: readAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetReadPostsForUserRow) int {
+		return cmp.Or(b.ReadAt.Compare(a.ReadAt), compareUUID(a.ID, b.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) StarPost(ctx context.Context, arg database.StarPostParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userPost{userID: arg.UserID, postID: arg.PostID}
+	if err := s.checkUserPost("post_stars", key); err != nil {
+		return err
+	}
+	if _, ok := s.stars[key]; !ok {
+		s.stars[key] = arg.StarredAt
+	}
+	return nil
+}
+
+func (s *Store) UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userPost{userID: arg.UserID, postID: arg.PostID}
+	if _, ok := s.stars[key]; !ok {
+		return 0, nil
+	}
+	delete(s.stars, key)
+	return 1, nil
+}
+
+func (s *Store) GetStarredPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetStarredPostsForUserRow{}
+	for key, starredAt := range s.stars {
+		if key.userID != userID {
+			continue
+		}
+		post := s.posts[key.postID]
+		rows = append(rows, database.GetStarredPostsForUserRow{
+			ID: post.ID,
+			CreatedAt: post.CreatedAt,
+			UpdatedAt: post.UpdatedAt,
+			Title: post.Title,
+			Url: post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID: post.FeedID,
+			Guid: post.Guid,
+			Content: post.Content,
+			ContentHash: post.ContentHash,
+			RevisedAt: post.RevisedAt,
+			Simhash: post.Simhash,
+			ClusterID: post.ClusterID,
+			Author: post.Author,
+			Categories: post.Categories,
+			StarredAt: starredAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetStarredPostsForUserRow) int {
+		return cmp.Or(b.StarredAt.Compare(a.StarredAt), compareUUID(a.ID, b.ID))
+	})
+	return rows, nil
+}
+
+func (s *Store) AddToReadLater(ctx context.Context, arg database.AddToReadLaterParams) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userPost{userID: arg.UserID, postID: arg.PostID}
+	if err := s.checkUserPost("read_later", key); err != nil {
+		return err
+	}
+	if _, ok := s.readLater[key]; ok {
+		return nil
+	}
+
+	var last int32
+	for other, entry := range s.readLater {
+		if other.userID == arg.UserID {
+			last = max(last, entry.Position)
+		}
+	}
+	s.readLater[key] = database.ReadLater{
+		UserID: arg.UserID,
+		PostID: arg.PostID,
+		Position: last + 1,
+		AddedAt: arg.AddedAt,
+	}
+	return nil
+}
+
+func (s *Store) RemoveFromReadLater(ctx context.Context, arg database.RemoveFromReadLaterParams) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := userPost{userID: arg.UserID, postID: arg.PostID}
+	if _, ok := s.readLater[key]; !ok {
+		return 0, nil
+	}
+	delete(s.readLater, key)
+	return 1, nil
+}
+
+func (s *Store) GetReadLaterForUser(ctx context.Context, userID uuid.UUID) ([]database.GetReadLaterForUserRow, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.GetReadLaterForUserRow{}
+	for key, entry := range s.readLater {
+		if key.userID != userID {
+			continue
+		}
+		post := s.posts[key.postID]
+		rows = append(rows, database.GetReadLaterForUserRow{
+			ID: post.ID,
+			CreatedAt: post.CreatedAt,
+			UpdatedAt: post.UpdatedAt,
+			Title: post.Title,
+			Url: post.Url,
+			Description: post.Description,
+			PublishedAt: post.PublishedAt,
+			FeedID: post.FeedID,
+			Guid: post.Guid,
+			Content: post.Content,
+			ContentHash: post.ContentHash,
+			RevisedAt: post.RevisedAt,
+			Simhash: post.Simhash,
+			ClusterID: post.ClusterID,
+			Author: post.Author,
+			Categories: post.Categories,
+			Position: entry.Position,
+			AddedAt: entry.AddedAt,
+		})
+	}
+	slices.SortFunc(rows, func(a, b database.GetReadLaterForUserRow) int {
+		return cmp.Compare(a.Position, b.Position)
+	})
+	return rows, nil
+}
@@ -0,0 +1,62 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"slices"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/search"
+)
+
+func (s *Store) SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error) {
+	query, err := search.Parse(arg.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rows := []database.SearchPostsRow{}
+	for _, post := range s.posts {
+		text := post.Title + " " + post.Description.String + " " + post.Content.String
+		if !query.Match(text) {
+			continue
+		}
+		if arg.Since.Valid && post.PublishedAt.Before(arg.Since.Time) {
+			continue
+		}
+		if arg.Until.Valid && !post.PublishedAt.Before(arg.Until.Time) {
+			continue
+		}
+		if arg.UserID.Valid && !s.followsPost(arg.UserID.UUID, post) {
+			continue
+		}
+		if arg.FolderID.Valid && !s.postInFolder(arg.FolderID.UUID, post) {
+			continue
+		}
+
+		body := post.Content.String
+		if !post.Content.Valid {
+			body = post.Description.String
+		}
+		rows = append(rows, database.SearchPostsRow{
+			ID: post.ID,
+			Url: post.Url,
+			PublishedAt: post.PublishedAt,
+			Rank: float32(query.Rank(post.Title, post.Description.String, post.Content.String)),
+			FeedName: s.feeds[post.FeedID].Name,
+			TitleHeadline: query.Headline(post.Title, 0),
+			Snippet: query.Headline(body, 25),
+		})
+	}
+
+	slices.SortFunc(rows, func(a, b database.SearchPostsRow) int {
+		return cmp.Or(cmp.Compare(b.Rank, a.Rank), b.PublishedAt.Compare(a.PublishedAt))
+	})
+	if len(rows) > int(arg.Limit) {
+		rows = rows[:max(arg.Limit, 0)]
+	}
+	return rows, nil
+}
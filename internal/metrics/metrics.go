@@ -0,0 +1,149 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+)
+
+// latencyBuckets are the upper bounds (in seconds) of the fetch latency histogram
+var latencyBuckets = []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Metrics collects aggregator counters and renders them in Prometheus text format.
+// All methods are safe to call on a nil *Metrics, so the aggregator doesn't need
+// to care whether it runs inside the daemon or not.
+type Metrics struct {
+	mu				sync.Mutex
+	fetches			map[string]uint64
+	latencyCounts	[]uint64
+	latencySum		float64
+	latencyCount	uint64
+	postsInserted	uint64
+	dbErrors		uint64
+	queueLag		float64
+}
+
+func New() *Metrics {
+	return &Metrics{
+		fetches: make(map[string]uint64),
+		latencyCounts: make([]uint64, len(latencyBuckets)),
+	}
+}
+
+func (m *Metrics) ObserveFetch(status string, d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.fetches[status]++
+
+	seconds := d.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			m.latencyCounts[i]++
+		}
+	}
+	m.latencySum += seconds
+	m.latencyCount++
+}
+
+func (m *Metrics) AddPostsInserted(n int) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.postsInserted += uint64(n)
+}
+
+func (m *Metrics) IncDBErrors() {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.dbErrors++
+}
+
+func (m *Metrics) SetQueueLag(d time.Duration) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.queueLag = d.Seconds()
+}
+
+func (m *Metrics) WriteTo(w io.Writer) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var written int64
+	printf := func(format string, args ...any) error {
+		n, err := fmt.Fprintf(w, format, args...)
+		written += int64(n)
+		return err
+	}
+
+	statuses := make([]string, 0, len(m.fetches))
+	for status := range m.fetches {
+		statuses = append(statuses, status)
+	}
+	sort.Strings(statuses)
+
+	if err := printf("# HELP gator_feed_fetches_total Feed fetches by HTTP status class.\n# TYPE gator_feed_fetches_total counter\n"); err != nil {
+		return written, err
+	}
+	for _, status := range statuses {
+		if err := printf("gator_feed_fetches_total{status=%q} %d\n", status, m.fetches[status]); err != nil {
+			return written, err
+		}
+	}
+
+	if err := printf("# HELP gator_feed_fetch_duration_seconds Feed fetch latency.\n# TYPE gator_feed_fetch_duration_seconds histogram\n"); err != nil {
+		return written, err
+	}
+	for i, bound := range latencyBuckets {
+		if err := printf("gator_feed_fetch_duration_seconds_bucket{le=\"%g\"} %d\n", bound, m.latencyCounts[i]); err != nil {
+			return written, err
+		}
+	}
+	if err := printf("gator_feed_fetch_duration_seconds_bucket{le=\"+Inf\"} %d\n", m.latencyCount); err != nil {
+		return written, err
+	}
+	if err := printf("gator_feed_fetch_duration_seconds_sum %g\ngator_feed_fetch_duration_seconds_count %d\n", m.latencySum, m.latencyCount); err != nil {
+		return written, err
+	}
+
+	if err := printf("# HELP gator_posts_inserted_total Posts inserted into the database.\n# TYPE gator_posts_inserted_total counter\ngator_posts_inserted_total %d\n", m.postsInserted); err != nil {
+		return written, err
+	}
+	if err := printf("# HELP gator_queue_lag_seconds Time since the stalest feed was last fetched.\n# TYPE gator_queue_lag_seconds gauge\ngator_queue_lag_seconds %g\n", m.queueLag); err != nil {
+		return written, err
+	}
+	if err := printf("# HELP gator_db_errors_total Database errors seen by the aggregator.\n# TYPE gator_db_errors_total counter\ngator_db_errors_total %d\n", m.dbErrors); err != nil {
+		return written, err
+	}
+
+	return written, nil
+}
+
+// Handler serves the metrics; before is called on every scrape so gauges that
+// depend on outside state (like queue lag) can be refreshed
+func (m *Metrics) Handler(before func()) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if before != nil {
+			before()
+		}
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		m.WriteTo(w)
+	})
+}
@@ -0,0 +1,57 @@
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"grysha11/BlogAggregator/internal/storage"
+	"grysha11/BlogAggregator/sql/schema"
+	"grysha11/BlogAggregator/sql/sqlite"
+
+	"github.com/pressly/goose/v3"
+)
+
+var ErrSchemaBehind = errors.New("database schema is behind")
+
+// New returns a provider running the migrations built into the binary against db,
+// each backend has its own set of migrations
+func New(db *sql.DB, backend storage.Backend) (*goose.Provider, error) {
+	if backend == storage.SQLite {
+		migrations, err := fs.Sub(sqlite.Schema, "schema")
+		if err != nil {
+			return nil, err
+		}
+		return goose.NewProvider(goose.DialectSQLite3, db, migrations)
+	}
+	return goose.NewProvider(goose.DialectPostgres, db, schema.FS)
+}
+
+// Expected is the schema version this build of gator works with
+func Expected(p *goose.Provider) int64 {
+	sources := p.ListSources()
+	if len(sources) == 0 {
+		return 0
+	}
+	return sources[len(sources)-1].Version
+}
+
+// Check makes sure the database has every migration this build knows about applied
+func Check(ctx context.Context, db *sql.DB, backend storage.Backend) error {
+	p, err := New(db, backend)
+	if err != nil {
+		return err
+	}
+
+	current, err := p.GetDBVersion(ctx)
+	if err != nil {
+		return err
+	}
+
+	if expected := Expected(p); current < expected {
+		return fmt.Errorf("%w: it is at version %v, gator needs version %v\nRun `gator migrate up` to update it", ErrSchemaBehind, current, expected)
+	}
+	return nil
+}
@@ -0,0 +1,197 @@
+package rss
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultUserAgent	= "gator/1.0"
+	DefaultContactURL	= "https://github.com/grysha11/BlogAggregator"
+	DefaultHostInterval	= time.Second
+	DefaultMaxPerHost	= 2
+	defaultTimeout		= 30 * time.Second
+)
+
+var (
+	ErrDisallowedByRobots	= errors.New("feed path is disallowed by robots.txt")
+	ErrInvalidFeed			= errors.New("response isn't a valid feed")
+)
+
+// StatusError is returned when the feed is served with a status outside 2xx
+type StatusError struct {
+	StatusCode	int
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %v", e.StatusCode)
+}
+
+type Options struct {
+	// UserAgent is the product part of the User-Agent header, e.g. "gator/1.0"
+	UserAgent		string
+	// ContactURL is appended to the User-Agent so site owners know who is fetching
+	ContactURL		string
+	// HostInterval is the minimal time between two requests to the same host
+	HostInterval	time.Duration
+	// MaxPerHost limits how many requests run against the same host at once
+	MaxPerHost		int
+	// CheckRobots makes the client skip feeds whose path robots.txt disallows
+	CheckRobots		bool
+	Timeout			time.Duration
+}
+
+// Client fetches feeds while staying polite to the hosts serving them,
+// it is safe for concurrent use
+type Client struct {
+	httpClient	*http.Client
+	opts		Options
+
+	mu			sync.Mutex
+	hosts		map[string]*hostState
+}
+
+type hostState struct {
+	slots		chan struct{}
+	next		time.Time
+	robots		*robotsRules
+	robotsAt	time.Time
+}
+
+func NewClient(opts Options) *Client {
+	if opts.UserAgent == "" {
+		opts.UserAgent = DefaultUserAgent
+	}
+	if opts.ContactURL == "" {
+		opts.ContactURL = DefaultContactURL
+	}
+	if opts.HostInterval < 0 {
+		opts.HostInterval = 0
+	}
+	if opts.MaxPerHost <= 0 {
+		opts.MaxPerHost = DefaultMaxPerHost
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = defaultTimeout
+	}
+
+	return &Client{
+		httpClient: &http.Client{Timeout: opts.Timeout},
+		opts: opts,
+		hosts: make(map[string]*hostState),
+	}
+}
+
+func (c *Client) UserAgent() string {
+	return fmt.Sprintf("%v (+%v)", c.opts.UserAgent, c.opts.ContactURL)
+}
+
+func (c *Client) host(name string) *hostState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	h, ok := c.hosts[name]
+	if !ok {
+		h = &hostState{slots: make(chan struct{}, c.opts.MaxPerHost)}
+		c.hosts[name] = h
+	}
+	return h
+}
+
+// acquire waits for a free slot on the host and for its rate limit,
+// the returned func gives the slot back
+func (c *Client) acquire(ctx context.Context, h *hostState) (func(), error) {
+	select {
+	case h.slots <- struct{}{}:
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+	release := func() { <-h.slots }
+
+	c.mu.Lock()
+	now := time.Now()
+	start := h.next
+	if start.Before(now) {
+		start = now
+	}
+	h.next = start.Add(c.opts.HostInterval)
+	c.mu.Unlock()
+
+	if wait := time.Until(start); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+
+	return release, nil
+}
+
+// backOff keeps requests away from a host which asked us to slow down
+func (c *Client) backOff(h *hostState, resp *http.Response) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return
+	}
+
+	seconds, err := strconv.Atoi(strings.TrimSpace(resp.Header.Get("Retry-After")))
+	if err != nil || seconds <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if until := time.Now().Add(time.Duration(seconds) * time.Second); until.After(h.next) {
+		h.next = until
+	}
+}
+
+// maxBodySize guards against feeds which never end
+const maxBodySize = 20 << 20
+
+// get performs a rate limited GET request and reads the whole body,
+// the host slot is held until the body is read
+func (c *Client) get(ctx context.Context, rawURL string) (int, []byte, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return 0, nil, err
+	}
+	h := c.host(u.Host)
+
+	release, err := c.acquire(ctx, h)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer release()
+
+	req, err := http.NewRequestWithContext(ctx, "GET", rawURL, nil)
+	if err != nil {
+		return 0, nil, err
+	}
+	req.Header.Set("User-Agent", c.UserAgent())
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return 0, nil, err
+	}
+	defer resp.Body.Close()
+	c.backOff(h, resp)
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxBodySize))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return resp.StatusCode, data, nil
+}
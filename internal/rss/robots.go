@@ -0,0 +1,132 @@
+package rss
+
+import (
+	"bufio"
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// robotsTTL is how long a fetched robots.txt is trusted
+const robotsTTL = 24 * time.Hour
+
+type robotsRule struct {
+	allow	bool
+	prefix	string
+}
+
+// robotsRules are the rules of the robots.txt group which applies to us
+type robotsRules struct {
+	rules	[]robotsRule
+}
+
+// allowed picks the longest matching rule, Allow wins a tie like Google's parser does
+func (r *robotsRules) allowed(path string) bool {
+	if r == nil {
+		return true
+	}
+
+	best := -1
+	allow := true
+	for _, rule := range r.rules {
+		if rule.prefix == "" || !strings.HasPrefix(path, rule.prefix) {
+			continue
+		}
+		if len(rule.prefix) > best || (len(rule.prefix) == best && rule.allow) {
+			best = len(rule.prefix)
+			allow = rule.allow
+		}
+	}
+	return allow
+}
+
+// parseRobots returns the rules of the group naming agent, or of the "*" group if there is none
+func parseRobots(data string, agent string) *robotsRules {
+	agent = strings.ToLower(agent)
+
+	var own, wildcard *robotsRules
+	var current []*robotsRules
+	inAgents := false
+
+	scanner := bufio.NewScanner(strings.NewReader(data))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if i := strings.IndexByte(line, '#'); i >= 0 {
+			line = line[:i]
+		}
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		key = strings.ToLower(strings.TrimSpace(key))
+		value = strings.TrimSpace(value)
+
+		switch key {
+		case "user-agent":
+			if !inAgents {
+				current = nil
+				inAgents = true
+			}
+			name := strings.ToLower(value)
+			switch {
+			case name == "*":
+				if wildcard == nil {
+					wildcard = &robotsRules{}
+				}
+				current = append(current, wildcard)
+			case strings.HasPrefix(agent, name):
+				if own == nil {
+					own = &robotsRules{}
+				}
+				current = append(current, own)
+			}
+		case "allow", "disallow":
+			inAgents = false
+			for _, group := range current {
+				group.rules = append(group.rules, robotsRule{allow: key == "allow", prefix: value})
+			}
+		default:
+			inAgents = false
+		}
+	}
+
+	if own != nil {
+		return own
+	}
+	return wildcard
+}
+
+// robotsAllowed tells whether robots.txt of the feed's host lets us fetch its path
+func (c *Client) robotsAllowed(ctx context.Context, feedURL *url.URL) bool {
+	h := c.host(feedURL.Host)
+
+	c.mu.Lock()
+	rules, fetchedAt := h.robots, h.robotsAt
+	c.mu.Unlock()
+
+	if fetchedAt.IsZero() || time.Since(fetchedAt) > robotsTTL {
+		robotsURL := url.URL{Scheme: feedURL.Scheme, Host: feedURL.Host, Path: "/robots.txt"}
+		status, data, err := c.get(ctx, robotsURL.String())
+
+		// an unreachable or missing robots.txt doesn't restrict anything
+		rules = nil
+		if err == nil && status >= 200 && status < 300 {
+			product, _, _ := strings.Cut(c.opts.UserAgent, "/")
+			rules = parseRobots(string(data), product)
+		}
+
+		c.mu.Lock()
+		h.robots, h.robotsAt = rules, time.Now()
+		c.mu.Unlock()
+	}
+
+	path := feedURL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	if feedURL.RawQuery != "" {
+		path += "?" + feedURL.RawQuery
+	}
+	return rules.allowed(path)
+}
@@ -0,0 +1,31 @@
+package rss
+
+import "testing"
+
+func TestParseRobots(t *testing.T) {
+	const agent = "gator"
+	tests := []struct {
+		name	string
+		robots	string
+		path	string
+		want	bool
+	}{
+		{name: "empty file", robots: "", path: "/feed.xml", want: true},
+		{name: "everything disallowed", robots: "User-agent: *\nDisallow: /", path: "/feed.xml", want: false},
+		{name: "empty disallow", robots: "User-agent: *\nDisallow:", path: "/feed.xml", want: true},
+		{name: "other path", robots: "User-agent: *\nDisallow: /private/", path: "/feed.xml", want: true},
+		{name: "longest rule wins", robots: "User-agent: *\nDisallow: /\nAllow: /feeds/", path: "/feeds/all.xml", want: true},
+		{name: "allow wins a tie", robots: "User-agent: *\nDisallow: /feed\nAllow: /feed", path: "/feed", want: true},
+		{name: "own group before wildcard", robots: "User-agent: *\nDisallow: /\n\nUser-agent: gator\nAllow: /", path: "/feed.xml", want: true},
+		{name: "own group disallows", robots: "User-agent: gator\nDisallow: /feed.xml\n\nUser-agent: *\nAllow: /", path: "/feed.xml", want: false},
+		{name: "group of another agent", robots: "User-agent: googlebot\nDisallow: /", path: "/feed.xml", want: true},
+		{name: "agents sharing a group", robots: "User-agent: googlebot\nUser-agent: gator\nDisallow: /", path: "/feed.xml", want: false},
+		{name: "case and comments", robots: "USER-AGENT: Gator # us\nDISALLOW: /feed.xml # no", path: "/feed.xml", want: false},
+		{name: "lines without a colon", robots: "garbage\nUser-agent: *\nnonsense\nDisallow: /", path: "/feed.xml", want: false},
+	}
+	for _, test := range tests {
+		if got := parseRobots(test.robots, agent).allowed(test.path); got != test.want {
+			t.Errorf("%v: allowed(%q) = %v, want %v", test.name, test.path, got, test.want)
+		}
+	}
+}
@@ -3,9 +3,9 @@ package rss
 import (
 	"context"
 	"encoding/xml"
+	"fmt"
 	"html"
-	"io"
-	"net/http"
+	"net/url"
 )
 
 type RSSFeed struct {
@@ -22,32 +22,42 @@ type RSSItem struct {
 	Link        string `xml:"link"`
 	Description string `xml:"description"`
 	PubDate     string `xml:"pubDate"`
+	GUID        string `xml:"guid"`
+	Content     string `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Author      string `xml:"author"`
+	Creator     string `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories  []string `xml:"category"`
 }
 
+var defaultClient = NewClient(Options{HostInterval: DefaultHostInterval})
+
+// FetchFeed fetches a feed with the default client
 func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", feedURL, nil)
+	return defaultClient.FetchFeed(ctx, feedURL)
+}
+
+func (c *Client) FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
+	u, err := url.Parse(feedURL)
 	if err != nil {
 		return &RSSFeed{}, err
 	}
 
-	req.Header.Set("User-Agent", "gator")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return &RSSFeed{}, err
+	if c.opts.CheckRobots && !c.robotsAllowed(ctx, u) {
+		return &RSSFeed{}, ErrDisallowedByRobots
 	}
-	defer resp.Body.Close()
 
-	data, err := io.ReadAll(resp.Body)
+	status, data, err := c.get(ctx, feedURL)
 	if err != nil {
 		return &RSSFeed{}, err
 	}
+	if status < 200 || status >= 300 {
+		return &RSSFeed{}, &StatusError{StatusCode: status}
+	}
 
 	var feed RSSFeed
 	err = xml.Unmarshal(data, &feed)
 	if err != nil {
-		return &RSSFeed{}, err
+		return &RSSFeed{}, fmt.Errorf("%w: %v", ErrInvalidFeed, err)
 	}
 
 	feed.Channel.Title = html.UnescapeString(feed.Channel.Title)
@@ -55,6 +65,7 @@ func FetchFeed(ctx context.Context, feedURL string) (*RSSFeed, error) {
 	for i := range feed.Channel.Item {
 		feed.Channel.Item[i].Title = html.UnescapeString(feed.Channel.Item[i].Title)
 		feed.Channel.Item[i].Description = html.UnescapeString(feed.Channel.Item[i].Description)
+		feed.Channel.Item[i].Content = html.UnescapeString(feed.Channel.Item[i].Content)
 	}
 
 	return &feed, nil
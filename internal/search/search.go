@@ -0,0 +1,292 @@
+// Package search evaluates the to_tsquery text service.ParseSearchQuery builds without Postgres.
+// There is no dictionary, words only lose a few common English endings.
+package search
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+type Query struct {
+	root	*tsNode
+}
+
+// Parse reads a to_tsquery expression
+func Parse(text string) (*Query, error) {
+	p := &tsParser{text: []rune(text)}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpace()
+	if p.pos < len(p.text) {
+		return nil, errors.New("unexpected text in search query")
+	}
+	return &Query{root: root}, nil
+}
+
+// Match tells whether text satisfies the query
+func (q *Query) Match(text string) bool {
+	return q.root.match(splitWords(text))
+}
+
+// Rank scores a post, title, description and content weigh like the A, B and C weights of ts_rank
+func (q *Query) Rank(title, description, content string) float64 {
+	rank := 0.0
+	weights := []float64{1.0, 0.4, 0.2}
+	for i, text := range []string{title, description, content} {
+		words := splitWords(text)
+		if len(words) == 0 {
+			continue
+		}
+		rank += weights[i] * float64(len(q.root.hitPositions(words))) / float64(len(words))
+	}
+	return rank
+}
+
+// Headline marks the hits in text with ** like ts_headline does,
+// with maxWords above zero only the part around the first hit is kept
+func (q *Query) Headline(text string, maxWords int) string {
+	return q.root.headline(text, maxWords)
+}
+
+type tsNodeKind int
+
+const (
+	tsTerm tsNodeKind = iota
+	tsAnd
+	tsOr
+	tsNot
+)
+
+type tsNode struct {
+	kind		tsNodeKind
+	words		[]string
+	prefix		bool
+	children	[]*tsNode
+}
+
+type tsParser struct {
+	text	[]rune
+	pos		int
+}
+
+func (p *tsParser) skipSpace() {
+	for p.pos < len(p.text) && unicode.IsSpace(p.text[p.pos]) {
+		p.pos++
+	}
+}
+
+func (p *tsParser) peek() rune {
+	p.skipSpace()
+	if p.pos >= len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *tsParser) parseOr() (*tsNode, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '|' {
+		p.pos++
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{kind: tsOr, children: []*tsNode{left, right}}
+	}
+	return left, nil
+}
+
+func (p *tsParser) parseAnd() (*tsNode, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek() == '&' {
+		p.pos++
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &tsNode{kind: tsAnd, children: []*tsNode{left, right}}
+	}
+	return left, nil
+}
+
+func (p *tsParser) parseUnary() (*tsNode, error) {
+	switch p.peek() {
+	case '!':
+		p.pos++
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &tsNode{kind: tsNot, children: []*tsNode{child}}, nil
+	case '(':
+		p.pos++
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek() != ')' {
+			return nil, errors.New("missing ) in search query")
+		}
+		p.pos++
+		return node, nil
+	case '\'':
+		return p.parseLexeme()
+	}
+	return nil, errors.New("unexpected text in search query")
+}
+
+// parseLexeme reads a quoted lexeme, '' and \ escape the next character
+func (p *tsParser) parseLexeme() (*tsNode, error) {
+	p.pos++
+	var text strings.Builder
+	for {
+		if p.pos >= len(p.text) {
+			return nil, errors.New("unterminated lexeme in search query")
+		}
+		r := p.text[p.pos]
+		p.pos++
+		if r == '\\' && p.pos < len(p.text) {
+			text.WriteRune(p.text[p.pos])
+			p.pos++
+			continue
+		}
+		if r == '\'' {
+			if p.pos < len(p.text) && p.text[p.pos] == '\'' {
+				text.WriteRune('\'')
+				p.pos++
+				continue
+			}
+			break
+		}
+		text.WriteRune(r)
+	}
+
+	node := &tsNode{kind: tsTerm, words: splitWords(text.String())}
+	if strings.HasPrefix(string(p.text[p.pos:]), ":*") {
+		node.prefix = true
+		p.pos += 2
+	}
+	return node, nil
+}
+
+func splitWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func stem(word string) string {
+	for _, suffix := range []string{"ing", "ed", "es", "s"} {
+		if len(word) > len(suffix)+2 && strings.HasSuffix(word, suffix) {
+			return strings.TrimSuffix(word, suffix)
+		}
+	}
+	return word
+}
+
+func wordMatches(word, lexeme string, prefix bool) bool {
+	if prefix {
+		return strings.HasPrefix(word, lexeme)
+	}
+	return stem(word) == stem(lexeme)
+}
+
+// termAt tells whether the term (a word or a phrase) starts at words[i]
+func (n *tsNode) termAt(words []string, i int) bool {
+	if len(n.words) == 0 || i+len(n.words) > len(words) {
+		return false
+	}
+	for j, lexeme := range n.words {
+		last := j == len(n.words)-1
+		if !wordMatches(words[i+j], lexeme, n.prefix && last) {
+			return false
+		}
+	}
+	return true
+}
+
+func (n *tsNode) match(words []string) bool {
+	switch n.kind {
+	case tsAnd:
+		return n.children[0].match(words) && n.children[1].match(words)
+	case tsOr:
+		return n.children[0].match(words) || n.children[1].match(words)
+	case tsNot:
+		return !n.children[0].match(words)
+	}
+	for i := range words {
+		if n.termAt(words, i) {
+			return true
+		}
+	}
+	return false
+}
+
+func (n *tsNode) hitPositions(words []string) map[int]bool {
+	positions := make(map[int]bool)
+	var walk func(node *tsNode)
+	walk = func(node *tsNode) {
+		switch node.kind {
+		case tsNot:
+			return
+		case tsAnd, tsOr:
+			for _, child := range node.children {
+				walk(child)
+			}
+			return
+		}
+		for i := range words {
+			if node.termAt(words, i) {
+				for j := range node.words {
+					positions[i+j] = true
+				}
+			}
+		}
+	}
+	walk(n)
+	return positions
+}
+
+func (n *tsNode) headline(text string, maxWords int) string {
+	fields := strings.Fields(text)
+	words := make([]string, len(fields))
+	for i, field := range fields {
+		words[i] = strings.Join(splitWords(field), "")
+	}
+	positions := n.hitPositions(words)
+
+	start, end := 0, len(fields)
+	if maxWords > 0 && len(fields) > maxWords {
+		first := len(fields)
+		for i := range fields {
+			if positions[i] {
+				first = i
+				break
+			}
+		}
+		if first == len(fields) {
+			first = 0
+		}
+		start = max(0, first-maxWords/3)
+		end = min(len(fields), start+maxWords)
+	}
+
+	out := make([]string, 0, end-start)
+	for i := start; i < end; i++ {
+		if positions[i] {
+			out = append(out, "**"+fields[i]+"**")
+			continue
+		}
+		out = append(out, fields[i])
+	}
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+var ErrLastAdmin = errors.New("the last admin can't be deleted, make someone else an admin first")
+
+// DeleteUser removes user with everything that belongs only to them, all at once or not at all.
+// Owned feeds go to their longest standing other follower, the ones nobody else follows are deleted.
+func DeleteUser(s *State, user database.User) (handedOff int64, removedFeeds int64, err error) {
+	err = s.InTx(func(db Store) error {
+		if user.IsAdmin {
+			users, err := db.GetAllUsers(context.Background())
+			if err != nil {
+				return err
+			}
+			admins := 0
+			for _, other := range users {
+				if other.IsAdmin {
+					admins++
+				}
+			}
+			// the only user left may go, nobody would be without an admin
+			if admins <= 1 && len(users) > 1 {
+				return ErrLastAdmin
+			}
+		}
+
+		owned, err := db.GetOwnedFeedIDs(context.Background(), uuid.NullUUID{UUID: user.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		// feeds nobody else follows are left without an owner
+		handedOff, err = db.HandOffOwnedFeeds(context.Background(), database.HandOffOwnedFeedsParams{
+			UserID: user.ID,
+			UpdatedAt: time.Now().UTC(),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := db.DeleteUser(context.Background(), user.ID); err != nil {
+			return err
+		}
+
+		removedFeeds, err = db.DeleteOrphanedFeeds(context.Background(), owned)
+		if err != nil {
+			return err
+		}
+		// feeds nobody else followed were counted by the hand off too, they are gone now
+		handedOff -= removedFeeds
+		return nil
+	})
+	if err != nil {
+		return 0, 0, err
+	}
+	return handedOff, removedFeeds, nil
+}
@@ -0,0 +1,98 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/servicetest"
+
+	"github.com/google/uuid"
+)
+
+func TestDeleteUserHandsOffAndRemovesOwnedFeeds(t *testing.T) {
+	s := servicetest.NewState(t)
+	admin := servicetest.CreateUser(t, s, "admin", true)
+	alice := servicetest.CreateUser(t, s, "alice", false)
+	bob := servicetest.CreateUser(t, s, "bob", false)
+
+	shared := servicetest.CreateFeed(t, s, alice, "shared", "http://example.com/shared")
+	servicetest.Follow(t, s, bob, shared)
+	lonely := servicetest.CreateFeed(t, s, alice, "lonely", "http://example.com/lonely")
+	// a feed that lost its owner earlier, deleting alice has nothing to do with it
+	other, err := s.DB.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name: "other",
+		Url: "http://example.com/other",
+	})
+	if err != nil {
+		t.Fatalf("CreateFeed: %v", err)
+	}
+
+	handedOff, removed, err := service.DeleteUser(s, alice)
+	if err != nil {
+		t.Fatalf("service.DeleteUser: %v", err)
+	}
+	if handedOff != 1 || removed != 1 {
+		t.Errorf("handed off %v and removed %v feeds, want 1 and 1", handedOff, removed)
+	}
+
+	if _, err := s.DB.GetUserByID(context.Background(), alice.ID); err != sql.ErrNoRows {
+		t.Errorf("alice is still there: %v", err)
+	}
+	feed, err := s.DB.GetFeedByURL(context.Background(), shared.Url)
+	if err != nil {
+		t.Fatalf("shared feed is gone: %v", err)
+	}
+	if feed.UserID.UUID != bob.ID {
+		t.Errorf("shared feed went to %v, want bob", feed.UserID.UUID)
+	}
+	if _, err := s.DB.GetFeedByURL(context.Background(), lonely.Url); err != sql.ErrNoRows {
+		t.Errorf("feed nobody follows anymore is still there: %v", err)
+	}
+	if _, err := s.DB.GetFeedByURL(context.Background(), other.Url); err != nil {
+		t.Errorf("feed alice never owned was deleted: %v", err)
+	}
+	if _, err := s.DB.GetUserByID(context.Background(), admin.ID); err != nil {
+		t.Errorf("admin is gone: %v", err)
+	}
+}
+
+func TestDeleteUserKeepsTheLastAdmin(t *testing.T) {
+	s := servicetest.NewState(t)
+	admin := servicetest.CreateUser(t, s, "admin", true)
+	servicetest.CreateUser(t, s, "alice", false)
+
+	if _, _, err := service.DeleteUser(s, admin); !errors.Is(err, service.ErrLastAdmin) {
+		t.Fatalf("service.DeleteUser of the last admin = %v, want service.ErrLastAdmin", err)
+	}
+	if _, err := s.DB.GetUserByID(context.Background(), admin.ID); err != nil {
+		t.Fatalf("last admin was deleted: %v", err)
+	}
+
+	second := servicetest.CreateUser(t, s, "second", true)
+	if _, _, err := service.DeleteUser(s, admin); err != nil {
+		t.Fatalf("service.DeleteUser with another admin left: %v", err)
+	}
+	if _, _, err := service.DeleteUser(s, second); !errors.Is(err, service.ErrLastAdmin) {
+		t.Errorf("service.DeleteUser of the new last admin = %v, want service.ErrLastAdmin", err)
+	}
+}
+
+func TestDeleteUserCanDeleteTheOnlyUser(t *testing.T) {
+	s := servicetest.NewState(t)
+	admin := servicetest.CreateUser(t, s, "admin", true)
+
+	if _, _, err := service.DeleteUser(s, admin); err != nil {
+		t.Fatalf("DeleteUser of the only user: %v", err)
+	}
+	if _, err := s.DB.GetUserByID(context.Background(), admin.ID); err != sql.ErrNoRows {
+		t.Errorf("only user is still there: %v", err)
+	}
+}
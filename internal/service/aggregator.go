@@ -3,69 +3,120 @@ package service
 import (
 	"fmt"
 	"time"
+	"errors"
 	"context"
 	"database/sql"
-	"strings"
 	"grysha11/BlogAggregator/internal/database"
 	"grysha11/BlogAggregator/internal/rss"
-	"github.com/google/uuid"
 )
 
 func ScrapeFeeds(s *State) {
 	feed, err := s.DB.GetNextFeedToFetch(context.Background())
 	if err != nil {
-		fmt.Printf("Couldn't get next feed to fetch: %v\n", err)
+		if err == sql.ErrNoRows {
+			s.Logger.Info("no feeds to fetch")
+			return
+		}
+		s.Metrics.IncDBErrors()
+		s.Logger.Error("couldn't get next feed to fetch", "error", err)
+		return
+	}
+
+	start := time.Now()
+	inserted, err := ScrapeFeed(s, feed)
+	if err != nil {
+		s.Logger.Error("couldn't collect feed", "feed_id", feed.ID, "url", feed.Url, "duration", time.Since(start), "error", err)
 		return
 	}
 
-	_, err = s.DB.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
-		LastFetchedAt: sql.NullTime{Time: time.Now(), Valid: true},
+	s.Logger.Info("feed collected", "feed_id", feed.ID, "url", feed.Url, "new_posts", inserted, "duration", time.Since(start))
+}
+
+// fetchStatus labels a fetch by the class of the status it was served with,
+// fetches which never got a response are "network" and the ones robots.txt kept us from are "robots"
+func fetchStatus(err error) string {
+	var statusErr *rss.StatusError
+	switch {
+	case err == nil, errors.Is(err, rss.ErrInvalidFeed):
+		return "2xx"
+	case errors.As(err, &statusErr):
+		return fmt.Sprintf("%vxx", statusErr.StatusCode/100)
+	case errors.Is(err, rss.ErrDisallowedByRobots):
+		return "robots"
+	default:
+		return "network"
+	}
+}
+
+// ScrapeFeed fetches one feed right away and stores its posts,
+// returning how many of them were new
+func ScrapeFeed(s *State, feed database.Feed) (int, error) {
+	_, err := s.DB.MarkFeedFetched(context.Background(), database.MarkFeedFetchedParams{
+		LastFetchedAt: sql.NullTime{Time: time.Now().UTC(), Valid: true},
 		UpdatedAt: time.Now().UTC(),
 		ID: feed.ID,
 	})
 	if err != nil {
-		fmt.Printf("Couldn't mark fetch time of feed %v: %v\n", feed.Name, err)
-		return
+		s.Metrics.IncDBErrors()
+		return 0, fmt.Errorf("couldn't mark fetch time: %v", err)
 	}
 
-	rssFeed, err := rss.FetchFeed(context.Background(), feed.Url)
+	start := time.Now()
+	rssFeed, err := s.Fetcher.FetchFeed(context.Background(), feed.Url)
 	if err != nil {
-		fmt.Printf("Couldn't fetch feed %v: %v", feed.Name, err)
+		s.Metrics.ObserveFetch(fetchStatus(err), time.Since(start))
+		return 0, fmt.Errorf("couldn't fetch feed: %v", err)
 	}
+	s.Metrics.ObserveFetch(fetchStatus(nil), time.Since(start))
+	s.Logger.Debug("feed fetched", "feed_id", feed.ID, "url", feed.Url, "items", len(rssFeed.Channel.Item), "duration", time.Since(start))
 
+	policy, err := FeedRetention(s, feed)
+	if err != nil {
+		return 0, err
+	}
+
+	var newPosts []database.Post
 	for _, item := range rssFeed.Channel.Item {
 		pubDate, err := time.Parse(time.RFC1123Z, item.PubDate)
 		if err != nil {
 			pubDate, err = time.Parse(time.RFC1123, item.PubDate)
 			if err != nil {
-				fmt.Printf("Could not parse Date time <%v> of item %v err: %v\n", item.PubDate, item.Title, err)
+				s.Logger.Warn("couldn't parse item date", "feed_id", feed.ID, "url", item.Link, "pub_date", item.PubDate, "error", err)
 				continue
 			}
-		} 
+		}
+		// the offset of the feed only tells how to read its dates, posts are kept in UTC
+		pubDate = pubDate.UTC()
 
-		description := sql.NullString{}
-		if item.Description != "" {
-			description.String = item.Description
-			description.Valid = true
+		// would be pruned right away and come back as new on the next fetch
+		if policy.MaxAge > 0 && time.Since(pubDate) > policy.MaxAge {
+			continue
 		}
 
-		_, err = s.DB.CreatePost(context.Background(), database.CreatePostParams{
-			ID: uuid.New(),
-			CreatedAt: time.Now().UTC(),
-			UpdatedAt: time.Now().UTC(),
-			Title: item.Title,
-			Url: item.Link,
-			Description: description,
-			PublishedAt: pubDate,
-			FeedID: feed.ID,
-		})
+		post, created, err := storeItem(s, feed, item, pubDate)
 		if err != nil {
-			if strings.Contains(err.Error(), "duplicate key") {
-				continue
-			}
-			fmt.Printf("Could not create post for db: %v\n", err)
+			s.Metrics.IncDBErrors()
+			s.Logger.Error("couldn't store post", "feed_id", feed.ID, "url", item.Link, "error", err)
+			continue
+		}
+		if created {
+			newPosts = append(newPosts, post)
 		}
 	}
+	s.Metrics.AddPostsInserted(len(newPosts))
+
+	// the first fetch of a feed brings its whole backlog, which isn't news to announce
+	if feed.LastFetchedAt.Valid {
+		FireHooks(s, feed, newPosts)
+	}
+
+	pruned, err := PruneFeed(s, feed, false)
+	if err != nil {
+		s.Metrics.IncDBErrors()
+		s.Logger.Error("couldn't prune posts", "feed_id", feed.ID, "url", feed.Url, "error", err)
+	} else if len(pruned) > 0 {
+		s.Logger.Info("pruned old posts", "feed_id", feed.ID, "url", feed.Url, "posts", len(pruned))
+	}
 
-	fmt.Printf("Feed %v is collected, %v posts scanned\n", feed.Name, len(rssFeed.Channel.Item))
-}
\ No newline at end of file
+	return len(newPosts), nil
+}
@@ -0,0 +1,158 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"grysha11/BlogAggregator/internal/metrics"
+	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/servicetest"
+)
+
+func TestScrapeFeedStoresNewPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	published := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+	server := servicetest.NewFeedServer(t,
+		servicetest.Item{Title: "First", Link: "http://example.com/1", GUID: "1", Description: "one", PubDate: published},
+		servicetest.Item{Title: "Second", Link: "http://example.com/2", GUID: "2", Description: "two", PubDate: published.Add(time.Minute)},
+	)
+	user := servicetest.CreateUser(t, s, "alice", true)
+	feed := servicetest.CreateFeed(t, s, user, "test", server.URL)
+
+	inserted, err := service.ScrapeFeed(s, feed)
+	if err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+	if inserted != 2 {
+		t.Fatalf("inserted = %v, want 2", inserted)
+	}
+
+	// nothing changed, so nothing is new the second time
+	inserted, err = service.ScrapeFeed(s, feed)
+	if err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+	if inserted != 0 {
+		t.Fatalf("inserted on refetch = %v, want 0", inserted)
+	}
+
+	post, err := s.DB.GetPostByURL(context.Background(), "http://example.com/1")
+	if err != nil {
+		t.Fatalf("GetPostByURL: %v", err)
+	}
+	if post.Title != "First" || !post.PublishedAt.Equal(published) {
+		t.Errorf("stored post = %q at %v, want %q at %v", post.Title, post.PublishedAt, "First", published)
+	}
+}
+
+func TestScrapeFeedRevisesEditedPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	published := time.Now().UTC().Add(-time.Hour)
+	server := servicetest.NewFeedServer(t,
+		servicetest.Item{Title: "Release", Link: "http://example.com/release", GUID: "r", Description: "version 1", PubDate: published},
+	)
+	user := servicetest.CreateUser(t, s, "alice", true)
+	feed := servicetest.CreateFeed(t, s, user, "test", server.URL)
+
+	if _, err := service.ScrapeFeed(s, feed); err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+	original, err := s.DB.GetPostByURL(context.Background(), "http://example.com/release")
+	if err != nil {
+		t.Fatalf("GetPostByURL: %v", err)
+	}
+	if original.RevisedAt.Valid {
+		t.Fatalf("new post is marked as revised")
+	}
+
+	server.SetItems(servicetest.Item{Title: "Release", Link: "http://example.com/release", GUID: "r", Description: "version 2", PubDate: published})
+	inserted, err := service.ScrapeFeed(s, feed)
+	if err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+	if inserted != 0 {
+		t.Errorf("an edited post counted as new")
+	}
+
+	revised, err := s.DB.GetPostByID(context.Background(), original.ID)
+	if err != nil {
+		t.Fatalf("GetPostByID: %v", err)
+	}
+	if !revised.RevisedAt.Valid || revised.Description.String != "version 2" {
+		t.Errorf("post after edit = %q revised %v, want %q revised", revised.Description.String, revised.RevisedAt.Valid, "version 2")
+	}
+
+	revision, err := s.DB.GetLatestPostRevision(context.Background(), original.ID)
+	if err != nil {
+		t.Fatalf("GetLatestPostRevision: %v", err)
+	}
+	if revision.Description.String != "version 1" || revision.ContentHash != original.ContentHash {
+		t.Errorf("revision keeps %q, want the old %q", revision.Description.String, "version 1")
+	}
+}
+
+func TestScrapeFeedFollowsMovedLinks(t *testing.T) {
+	s := servicetest.NewState(t)
+	published := time.Now().UTC().Add(-time.Hour)
+	server := servicetest.NewFeedServer(t,
+		servicetest.Item{Title: "Moved", Link: "http://example.com/old", GUID: "m", Description: "text", PubDate: published},
+	)
+	user := servicetest.CreateUser(t, s, "alice", true)
+	feed := servicetest.CreateFeed(t, s, user, "test", server.URL)
+
+	if _, err := service.ScrapeFeed(s, feed); err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+
+	server.SetItems(servicetest.Item{Title: "Moved", Link: "http://example.com/new", GUID: "m", Description: "text", PubDate: published})
+	if _, err := service.ScrapeFeed(s, feed); err != nil {
+		t.Fatalf("service.ScrapeFeed: %v", err)
+	}
+
+	post, err := s.DB.GetPostByURL(context.Background(), "http://example.com/new")
+	if err != nil {
+		t.Fatalf("post isn't found under its new link: %v", err)
+	}
+	if post.RevisedAt.Valid {
+		t.Errorf("a moved link counted as an edit")
+	}
+	if _, err := s.DB.GetPostByURL(context.Background(), "http://example.com/old"); err != sql.ErrNoRows {
+		t.Errorf("old link still finds a post: %v", err)
+	}
+}
+
+func TestScrapeFeedCountsFetchesByStatusClass(t *testing.T) {
+	s := servicetest.NewState(t)
+	s.Metrics = metrics.New()
+	user := servicetest.CreateUser(t, s, "alice", true)
+
+	ok := servicetest.NewFeedServer(t)
+	missing := httptest.NewServer(http.NotFoundHandler())
+	defer missing.Close()
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	down := httptest.NewServer(http.NotFoundHandler())
+	down.Close()
+
+	for _, url := range []string{ok.URL, missing.URL, broken.URL, down.URL} {
+		feed := servicetest.CreateFeed(t, s, user, url, url)
+		service.ScrapeFeed(s, feed)
+	}
+
+	var out strings.Builder
+	if _, err := s.Metrics.WriteTo(&out); err != nil {
+		t.Fatalf("WriteTo: %v", err)
+	}
+	for _, status := range []string{"2xx", "4xx", "5xx", "network"} {
+		if want := `gator_feed_fetches_total{status="` + status + `"} 1`; !strings.Contains(out.String(), want) {
+			t.Errorf("metrics don't have %v:\n%v", want, out.String())
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"html"
+	"math/bits"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// posts further apart than this in publishing time are not the same story
+	clusterWindow = 3 * 24 * time.Hour
+	// how many of the 64 simhash bits may differ between posts of one cluster
+	maxSimhashDistance = 3
+	// shorter texts share too many words by chance to be fingerprinted
+	minSimhashWords = 6
+)
+
+var htmlTag = regexp.MustCompile(`<[^>]*>`)
+
+// Simhash fingerprints a post by the words of its title and description, so that posts
+// telling the same story get fingerprints differing in a few bits only.
+// It returns 0 when there isn't enough text to tell.
+func Simhash(title, description string) int64 {
+	text := html.UnescapeString(htmlTag.ReplaceAllString(description, " "))
+
+	weights := make(map[string]int)
+	addWords := func(text string, weight int) {
+		words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+		})
+		for _, word := range words {
+			if len([]rune(word)) >= 3 {
+				weights[word] += weight
+			}
+		}
+	}
+	// the title says the most about what a post is about
+	addWords(title, 2)
+	addWords(text, 1)
+
+	if len(weights) < minSimhashWords {
+		return 0
+	}
+
+	var sums [64]int
+	for word, weight := range weights {
+		h := fnv.New64a()
+		h.Write([]byte(word))
+		sum := h.Sum64()
+		for i := range sums {
+			if sum&(1<<i) != 0 {
+				sums[i] += weight
+			} else {
+				sums[i] -= weight
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for i, sum := range sums {
+		if sum > 0 {
+			fingerprint |= 1 << i
+		}
+	}
+	return int64(fingerprint)
+}
+
+// clusterPost puts a new post into the cluster of the oldest post from another feed
+// with a close enough fingerprint, starting a new cluster when that post has none yet
+func clusterPost(s *State, post database.Post) error {
+	if post.Simhash == 0 {
+		return nil
+	}
+
+	candidates, err := s.DB.GetClusterCandidates(context.Background(), database.GetClusterCandidatesParams{
+		FeedID: post.FeedID,
+		Since: post.PublishedAt.Add(-clusterWindow),
+		Until: post.PublishedAt.Add(clusterWindow),
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, candidate := range candidates {
+		if candidate.ID == post.ID || bits.OnesCount64(uint64(candidate.Simhash^post.Simhash)) > maxSimhashDistance {
+			continue
+		}
+
+		clusterID := candidate.ClusterID
+		if !clusterID.Valid {
+			clusterID = uuid.NullUUID{UUID: uuid.New(), Valid: true}
+			err := s.DB.SetPostCluster(context.Background(), database.SetPostClusterParams{
+				ID: candidate.ID,
+				ClusterID: clusterID,
+			})
+			if err != nil {
+				return err
+			}
+		}
+
+		return s.DB.SetPostCluster(context.Background(), database.SetPostClusterParams{
+			ID: post.ID,
+			ClusterID: clusterID,
+		})
+	}
+
+	return nil
+}
+
+// AlsoIn names the feeds other than the browsed one the story of post showed up in,
+// either as a post of its cluster or with the very same link
+func AlsoIn(s *State, user database.User, post database.GetPostsForUserRow) ([]string, error) {
+	return s.DB.GetClusterFeedNames(context.Background(), database.GetClusterFeedNamesParams{
+		UserID: user.ID,
+		FeedID: post.BrowsedFeedID,
+		ClusterID: post.ClusterID,
+		PostID: post.ID,
+	})
+}
@@ -0,0 +1,72 @@
+package service_test
+
+import (
+	"math/bits"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func TestSimhash(t *testing.T) {
+	const story = "Go 1.30 released with faster generics and a new garbage collector"
+	tests := []struct {
+		name				string
+		title, description	string
+		other				[2]string
+		// the most bits the fingerprints may differ in, or the least when apart is set
+		bits	int
+		apart	bool
+	}{
+		{
+			name: "same text",
+			title: story, description: "The release notes list every change.",
+			other: [2]string{story, "The release notes list every change."},
+			bits: 0,
+		},
+		{
+			name: "case, markup and punctuation",
+			title: story, description: "The release notes list every change.",
+			other: [2]string{"GO 1.30 RELEASED, with faster generics and a new garbage collector!", "<p>The <b>release</b> notes list every change</p>"},
+			bits: 0,
+		},
+		{
+			name: "retold",
+			title: story, description: "The release notes list every change in detail.",
+			other: [2]string{"Go 1.30 is released with faster generics and a new garbage collector", "The release notes list every single change in detail."},
+			bits: 3,
+		},
+		{
+			name: "another story",
+			title: story, description: "The release notes list every change.",
+			other: [2]string{"Rust foundation elects a new board of directors", "Members voted over the weekend on the candidates."},
+			bits: 4, apart: true,
+		},
+	}
+	for _, test := range tests {
+		a := service.Simhash(test.title, test.description)
+		b := service.Simhash(test.other[0], test.other[1])
+		if a == 0 || b == 0 {
+			t.Errorf("%v: no fingerprint, %v and %v", test.name, a, b)
+			continue
+		}
+		distance := bits.OnesCount64(uint64(a ^ b))
+		if test.apart && distance < test.bits || !test.apart && distance > test.bits {
+			t.Errorf("%v: fingerprints differ in %v bits", test.name, distance)
+		}
+	}
+}
+
+func TestSimhashOfShortTexts(t *testing.T) {
+	tests := [][2]string{
+		{"", ""},
+		{"Hello", ""},
+		{"A new post", "is up"},
+		// words shorter than three letters don't count
+		{"a b c de fg hi", "jk lm no pq rs tu"},
+	}
+	for _, test := range tests {
+		if got := service.Simhash(test[0], test[1]); got != 0 {
+			t.Errorf("Simhash(%q, %q) = %v, want 0", test[0], test[1], got)
+		}
+	}
+}
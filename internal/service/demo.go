@@ -0,0 +1,27 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const DemoUser = "demo"
+
+// SeedDemo sets up an empty store for the --ephemeral demo, with a logged in admin to play with
+func SeedDemo(s *State) error {
+	user, err := s.DB.CreateUser(context.Background(), database.CreateUserParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name: DemoUser,
+		IsAdmin: true,
+	})
+	if err != nil {
+		return err
+	}
+	return s.Config.SetUser(user.Name)
+}
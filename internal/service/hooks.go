@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/hooks"
+
+	"github.com/google/uuid"
+)
+
+// hookRetryDelay is the wait before the second attempt, it doubles with every next one
+const hookRetryDelay = time.Second
+
+const (
+	hookWorkers		= 4
+	// hookQueueSize bounds the deliveries waiting for a worker, the ones past it fail right away
+	hookQueueSize	= 256
+	// hookDeadline bounds a delivery with all its attempts and the waits between them
+	hookDeadline	= 2 * time.Minute
+)
+
+var errCommandHooksDisabled = errors.New("command hooks are disabled, set allow_command_hooks in the config of the aggregator")
+
+var errHookQueueFull = errors.New("too many deliveries are waiting, this one was dropped")
+
+type hookDelivery struct {
+	hook	database.Hook
+	feed	database.Feed
+	post	database.Post
+}
+
+// HookQueue delivers hooks in the background, so fetching never waits for a slow receiver
+type HookQueue struct {
+	s			*State
+	deliveries	chan hookDelivery
+	wg			sync.WaitGroup
+	closeOnce	sync.Once
+}
+
+func newHookQueue(s *State) *HookQueue {
+	q := &HookQueue{
+		s: s,
+		deliveries: make(chan hookDelivery, hookQueueSize),
+	}
+	for range hookWorkers {
+		q.wg.Add(1)
+		go func() {
+			defer q.wg.Done()
+			for d := range q.deliveries {
+				q.deliver(d)
+			}
+		}()
+	}
+	return q
+}
+
+// Close waits for the queued deliveries to be done, nothing can be queued after it
+func (q *HookQueue) Close() {
+	q.closeOnce.Do(func() {
+		close(q.deliveries)
+	})
+	q.wg.Wait()
+}
+
+func (q *HookQueue) push(d hookDelivery) {
+	select {
+	case q.deliveries <- d:
+	default:
+		q.record(d, 0, errHookQueueFull)
+	}
+}
+
+func (q *HookQueue) deliver(d hookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), hookDeadline)
+	defer cancel()
+
+	attempts, err := deliverHook(ctx, q.s, d.hook, hookPost(d.feed, d.post))
+	q.record(d, attempts, err)
+}
+
+// record writes a delivery into the delivery log
+func (q *HookQueue) record(d hookDelivery, attempts int, err error) {
+	s := q.s
+	delivery := database.CreateHookDeliveryParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		HookID: d.hook.ID,
+		PostID: d.post.ID,
+		Attempts: int32(attempts),
+		Succeeded: err == nil,
+	}
+	if err != nil {
+		delivery.Error = nullString(err.Error())
+		s.Logger.Warn("hook delivery failed", "hook_id", d.hook.ID, "feed_id", d.feed.ID, "post_id", d.post.ID, "attempts", attempts, "error", err)
+	}
+
+	if _, err := s.DB.CreateHookDelivery(context.Background(), delivery); err != nil {
+		s.Metrics.IncDBErrors()
+		s.Logger.Error("couldn't log hook delivery", "hook_id", d.hook.ID, "post_id", d.post.ID, "error", err)
+	}
+}
+
+// FireHooks queues the new posts of feed for every hook watching the feed,
+// each delivery is recorded in the delivery log once it is done
+func FireHooks(s *State, feed database.Feed, posts []database.Post) {
+	if len(posts) == 0 {
+		return
+	}
+
+	feedHooks, err := s.DB.GetHooksForFeed(context.Background(), feed.ID)
+	if err != nil {
+		s.Metrics.IncDBErrors()
+		s.Logger.Error("couldn't get hooks", "feed_id", feed.ID, "url", feed.Url, "error", err)
+		return
+	}
+
+	for _, hook := range feedHooks {
+		for _, post := range posts {
+			s.Hooks.push(hookDelivery{hook: hook, feed: feed, post: post})
+		}
+	}
+}
+
+func deliverHook(ctx context.Context, s *State, hook database.Hook, post hooks.Post) (int, error) {
+	if hook.Kind == hooks.KindCommand && !s.Config.AllowCommandHooks {
+		return 0, errCommandHooksDisabled
+	}
+
+	var err error
+	attempt := 0
+	for attempt < int(max(hook.MaxAttempts, 1)) {
+		if attempt > 0 {
+			select {
+			case <-time.After(hookRetryDelay << (attempt - 1)):
+			case <-ctx.Done():
+				return attempt, fmt.Errorf("gave up after %v: %v", hookDeadline, err)
+			}
+		}
+		attempt++
+
+		switch hook.Kind {
+		case hooks.KindWebhook:
+			var body []byte
+			body, err = hooks.Render(hook.Template, post)
+			if err != nil {
+				return attempt, err
+			}
+			err = hooks.SendWebhook(ctx, hook.Target, s.Fetcher.UserAgent(), body)
+		case hooks.KindCommand:
+			err = hooks.RunCommand(ctx, hook.Target, post)
+		default:
+			return attempt, errors.New("unknown hook kind: " + hook.Kind)
+		}
+
+		if err == nil {
+			return attempt, nil
+		}
+	}
+
+	return attempt, err
+}
+
+func hookPost(feed database.Feed, post database.Post) hooks.Post {
+	return hooks.Post{
+		ID: post.ID,
+		Title: post.Title,
+		URL: post.Url,
+		Description: post.Description.String,
+		PublishedAt: post.PublishedAt,
+		Feed: hooks.Feed{
+			ID: feed.ID,
+			Name: feed.Name,
+			URL: feed.Url,
+		},
+	}
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+const (
+	MuteKeyword		= "keyword"
+	MuteRegex		= "regex"
+	MuteAuthor		= "author"
+	MuteCategory	= "category"
+)
+
+var MuteKinds = []string{MuteKeyword, MuteRegex, MuteAuthor, MuteCategory}
+
+// mutesBatch is how many posts are read at once while looking for ones which aren't muted
+const mutesBatch = 100
+
+// Mute is a rule ready to be matched against posts
+type Mute struct {
+	database.GetMutesForUserRow
+	re	*regexp.Regexp
+}
+
+// compileMute checks the pattern of a rule, keywords match whole words ignoring case
+func compileMute(kind, pattern string) (*regexp.Regexp, error) {
+	switch kind {
+	case MuteKeyword:
+		return regexp.Compile(`(?i)\b` + regexp.QuoteMeta(pattern) + `\b`)
+	case MuteRegex:
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex provided: %v", err)
+		}
+		return re, nil
+	case MuteAuthor, MuteCategory:
+		return nil, nil
+	}
+	return nil, fmt.Errorf("unknown mute kind: %v (expected one of %v)", kind, strings.Join(MuteKinds, ", "))
+}
+
+// CreateMute adds a rule hiding posts from user, for every followed feed when feed is nil
+func CreateMute(s *State, user database.User, kind, pattern string, feed *database.Feed) (database.Mute, error) {
+	if strings.TrimSpace(pattern) == "" {
+		return database.Mute{}, fmt.Errorf("mute pattern can't be empty")
+	}
+	if _, err := compileMute(kind, pattern); err != nil {
+		return database.Mute{}, err
+	}
+
+	params := database.CreateMuteParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UserID: user.ID,
+		Kind: kind,
+		Pattern: pattern,
+	}
+	if feed != nil {
+		params.FeedID = uuid.NullUUID{UUID: feed.ID, Valid: true}
+	}
+	return s.DB.CreateMute(context.Background(), params)
+}
+
+// UserMutes returns the rules of user ready to be matched
+func UserMutes(s *State, user database.User) ([]Mute, error) {
+	rows, err := s.DB.GetMutesForUser(context.Background(), user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	mutes := make([]Mute, 0, len(rows))
+	for _, row := range rows {
+		re, err := compileMute(row.Kind, row.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("mute %v is broken: %v", row.ID, err)
+		}
+		mutes = append(mutes, Mute{GetMutesForUserRow: row, re: re})
+	}
+	return mutes, nil
+}
+
+// Matches tells whether the rule hides post
+func (m Mute) Matches(post database.GetPostsForUserRow) bool {
+	if m.FeedID.Valid && m.FeedID.UUID != post.BrowsedFeedID {
+		return false
+	}
+
+	switch m.Kind {
+	case MuteKeyword, MuteRegex:
+		return m.re.MatchString(post.Title) || m.re.MatchString(post.Description.String) || m.re.MatchString(post.Content.String)
+	case MuteAuthor:
+		return post.Author != "" && strings.Contains(strings.ToLower(post.Author), strings.ToLower(m.Pattern))
+	case MuteCategory:
+		for _, category := range strings.Split(post.Categories, "\n") {
+			if strings.EqualFold(category, m.Pattern) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func muted(mutes []Mute, post database.GetPostsForUserRow) bool {
+	for _, mute := range mutes {
+		if mute.Matches(post) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisiblePosts reads a page like GetPostsForUser does, leaving out the posts the user muted.
+// Pages are filled up from further posts, so a page is only short when there are no more.
+// It also returns how many muted posts were skipped on the way.
+func VisiblePosts(s *State, user database.User, params database.GetPostsForUserParams) ([]database.GetPostsForUserRow, int, error) {
+	mutes, err := UserMutes(s, user)
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(mutes) == 0 {
+		posts, err := s.DB.GetPostsForUser(context.Background(), params)
+		return posts, 0, err
+	}
+
+	limit := params.Limit
+	params.Limit = max(limit, mutesBatch)
+
+	posts := []database.GetPostsForUserRow{}
+	hidden := 0
+	for {
+		batch, err := s.DB.GetPostsForUser(context.Background(), params)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		for _, post := range batch {
+			if muted(mutes, post) {
+				hidden++
+				continue
+			}
+			posts = append(posts, post)
+			if len(posts) == int(limit) {
+				return posts, hidden, nil
+			}
+		}
+
+		if len(batch) < int(params.Limit) {
+			return posts, hidden, nil
+		}
+
+		// go on after the last post of the batch
+		last := batch[len(batch)-1]
+		params.CursorTime = sql.NullTime{Time: last.PublishedAt, Valid: true}
+		if params.SortByFetched {
+			params.CursorTime.Time = last.CreatedAt
+		}
+		params.CursorID = last.ID
+	}
+}
+
+// MuteCounts tells how many of the posts user can browse each of the rules hides
+func MuteCounts(s *State, user database.User, mutes []Mute) (map[uuid.UUID]int, error) {
+	counts := make(map[uuid.UUID]int)
+	params := database.GetPostsForUserParams{
+		UserID: user.ID,
+		Limit: mutesBatch,
+	}
+	for {
+		batch, err := s.DB.GetPostsForUser(context.Background(), params)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, post := range batch {
+			for _, mute := range mutes {
+				if mute.Matches(post) {
+					counts[mute.ID]++
+				}
+			}
+		}
+
+		if len(batch) < int(params.Limit) {
+			return counts, nil
+		}
+		last := batch[len(batch)-1]
+		params.CursorTime = sql.NullTime{Time: last.PublishedAt, Valid: true}
+		params.CursorID = last.ID
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"errors"
+
+	"grysha11/BlogAggregator/internal/database"
+)
+
+var ErrNotFeedOwner = errors.New("only the owner of the feed or an admin can do that")
+
+// CanManageFeed tells whether user may rename, move or delete feed
+func CanManageFeed(user database.User, feed database.Feed) bool {
+	if user.IsAdmin {
+		return true
+	}
+	return feed.UserID.Valid && feed.UserID.UUID == user.ID
+}
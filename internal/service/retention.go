@@ -0,0 +1,145 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// RetentionPolicy limits how many posts of a feed are kept.
+// Zero values mean no limit, KeepUnread protects posts from both limits
+// and is on unless a feed or the config turns it off.
+type RetentionPolicy struct {
+	KeepLast	int32
+	MaxAge		time.Duration
+	KeepUnread	bool
+}
+
+func (p RetentionPolicy) IsZero() bool {
+	return p.KeepLast == 0 && p.MaxAge == 0
+}
+
+func (p RetentionPolicy) String() string {
+	if p.IsZero() {
+		return "keep everything"
+	}
+
+	var parts []string
+	if p.KeepLast > 0 {
+		parts = append(parts, fmt.Sprintf("keep last %v posts", p.KeepLast))
+	}
+	if p.MaxAge > 0 {
+		parts = append(parts, fmt.Sprintf("keep posts younger than %v", FormatRetentionAge(p.MaxAge)))
+	}
+	if p.KeepUnread {
+		parts = append(parts, "never delete unread posts")
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ParseRetentionAge accepts everything time.ParseDuration does plus whole days like "30d"
+func ParseRetentionAge(str string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(str, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid retention age: %v", str)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+
+	d, err := time.ParseDuration(str)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid retention age: %v", str)
+	}
+	return d, nil
+}
+
+func FormatRetentionAge(d time.Duration) string {
+	if d%(24*time.Hour) == 0 {
+		return fmt.Sprintf("%vd", int64(d/(24*time.Hour)))
+	}
+	return d.String()
+}
+
+// GlobalRetention is the policy from the config file, used for feeds without their own
+func GlobalRetention(s *State) (RetentionPolicy, error) {
+	policy := RetentionPolicy{
+		KeepLast: s.Config.RetentionKeepLast,
+		KeepUnread: s.Config.KeepUnread(),
+	}
+	if s.Config.RetentionMaxAge != "" {
+		maxAge, err := ParseRetentionAge(s.Config.RetentionMaxAge)
+		if err != nil {
+			return RetentionPolicy{}, err
+		}
+		policy.MaxAge = maxAge
+	}
+	return policy, nil
+}
+
+// FeedRetention returns the policy of the feed, every limit the feed doesn't set is taken from the global one
+func FeedRetention(s *State, feed database.Feed) (RetentionPolicy, error) {
+	policy, err := GlobalRetention(s)
+	if err != nil {
+		return RetentionPolicy{}, err
+	}
+
+	if feed.RetentionKeepLast.Valid {
+		policy.KeepLast = feed.RetentionKeepLast.Int32
+	}
+	if feed.RetentionMaxAgeSeconds.Valid {
+		policy.MaxAge = time.Duration(feed.RetentionMaxAgeSeconds.Int64) * time.Second
+	}
+	if feed.RetentionKeepUnread.Valid {
+		policy.KeepUnread = feed.RetentionKeepUnread.Bool
+	}
+	return policy, nil
+}
+
+// PruneFeed removes the posts of feed its retention policy doesn't keep.
+// With dryRun set nothing is deleted, the posts which would be removed are only returned.
+func PruneFeed(s *State, feed database.Feed, dryRun bool) ([]database.Post, error) {
+	policy, err := FeedRetention(s, feed)
+	if err != nil {
+		return nil, err
+	}
+	if policy.IsZero() {
+		return nil, nil
+	}
+
+	params := database.GetPrunablePostsParams{
+		FeedID: feed.ID,
+		KeepUnread: policy.KeepUnread,
+	}
+	if policy.KeepLast > 0 {
+		params.KeepLast = sql.NullInt32{Int32: policy.KeepLast, Valid: true}
+	}
+	if policy.MaxAge > 0 {
+		params.OlderThan = sql.NullTime{Time: time.Now().UTC().Add(-policy.MaxAge), Valid: true}
+	}
+
+	posts, err := s.DB.GetPrunablePosts(context.Background(), params)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun || len(posts) == 0 {
+		return posts, nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(posts))
+	for _, post := range posts {
+		ids = append(ids, post.ID)
+	}
+	if err := s.DB.DeletePostsByIDs(context.Background(), ids); err != nil {
+		return nil, err
+	}
+
+	return posts, nil
+}
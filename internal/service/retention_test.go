@@ -0,0 +1,100 @@
+package service_test
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"testing"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/service"
+	"grysha11/BlogAggregator/internal/servicetest"
+)
+
+func prunedTitles(posts []database.Post) []string {
+	var titles []string
+	for _, post := range posts {
+		titles = append(titles, post.Title)
+	}
+	slices.Sort(titles)
+	return titles
+}
+
+func TestPruneFeedKeepsUnreadAndStarredPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	user := servicetest.CreateUser(t, s, "alice", true)
+	feed := servicetest.CreateFeed(t, s, user, "test", "http://example.com/feed")
+	posts := servicetest.CreatePosts(t, s, feed, 5)
+
+	feed, err := s.DB.SetFeedRetention(context.Background(), database.SetFeedRetentionParams{
+		ID: feed.ID,
+		RetentionKeepLast: sql.NullInt32{Int32: 2, Valid: true},
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("SetFeedRetention: %v", err)
+	}
+
+	// posts 0 to 2 are past keep_last, 0 and 1 are read and 1 is starred as well
+	for _, post := range posts[:2] {
+		if err := s.DB.MarkPostRead(context.Background(), database.MarkPostReadParams{UserID: user.ID, PostID: post.ID, ReadAt: time.Now().UTC()}); err != nil {
+			t.Fatalf("MarkPostRead: %v", err)
+		}
+	}
+	if err := s.DB.StarPost(context.Background(), database.StarPostParams{UserID: user.ID, PostID: posts[1].ID, StarredAt: time.Now().UTC()}); err != nil {
+		t.Fatalf("StarPost: %v", err)
+	}
+
+	dryRun, err := service.PruneFeed(s, feed, true)
+	if err != nil {
+		t.Fatalf("service.PruneFeed dry run: %v", err)
+	}
+	if got := prunedTitles(dryRun); !slices.Equal(got, []string{"Post 0"}) {
+		t.Fatalf("dry run prunes %v, want [Post 0]", got)
+	}
+	if _, err := s.DB.GetPostByID(context.Background(), posts[0].ID); err != nil {
+		t.Fatalf("dry run deleted a post: %v", err)
+	}
+
+	pruned, err := service.PruneFeed(s, feed, false)
+	if err != nil {
+		t.Fatalf("service.PruneFeed: %v", err)
+	}
+	if got := prunedTitles(pruned); !slices.Equal(got, []string{"Post 0"}) {
+		t.Fatalf("pruned %v, want [Post 0]", got)
+	}
+	if _, err := s.DB.GetPostByID(context.Background(), posts[0].ID); err != sql.ErrNoRows {
+		t.Errorf("pruned post is still there: %v", err)
+	}
+	for _, post := range posts[1:] {
+		if _, err := s.DB.GetPostByID(context.Background(), post.ID); err != nil {
+			t.Errorf("%v was deleted: %v", post.Title, err)
+		}
+	}
+}
+
+func TestPruneFeedCanDeleteUnreadPosts(t *testing.T) {
+	s := servicetest.NewState(t)
+	user := servicetest.CreateUser(t, s, "alice", true)
+	feed := servicetest.CreateFeed(t, s, user, "test", "http://example.com/feed")
+	servicetest.CreatePosts(t, s, feed, 3)
+
+	feed, err := s.DB.SetFeedRetention(context.Background(), database.SetFeedRetentionParams{
+		ID: feed.ID,
+		RetentionKeepLast: sql.NullInt32{Int32: 1, Valid: true},
+		RetentionKeepUnread: sql.NullBool{Bool: false, Valid: true},
+		UpdatedAt: time.Now().UTC(),
+	})
+	if err != nil {
+		t.Fatalf("SetFeedRetention: %v", err)
+	}
+
+	pruned, err := service.PruneFeed(s, feed, false)
+	if err != nil {
+		t.Fatalf("service.PruneFeed: %v", err)
+	}
+	if got := prunedTitles(pruned); !slices.Equal(got, []string{"Post 0", "Post 1"}) {
+		t.Errorf("pruned %v, want [Post 0 Post 1]", got)
+	}
+}
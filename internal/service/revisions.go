@@ -0,0 +1,234 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/rss"
+
+	"github.com/google/uuid"
+)
+
+// storeItem inserts a new post for item, or updates the already known post and keeps
+// its previous version in post_revisions when the content has changed.
+// It reports whether a new post was created.
+func storeItem(s *State, feed database.Feed, item rss.RSSItem, pubDate time.Time) (database.Post, bool, error) {
+	hash := contentHash(item.Title, item.Description, item.Content)
+
+	// relative or otherwise odd links are stored the way the feed has them
+	if link, err := CanonicalURL(item.Link); err == nil {
+		item.Link = link
+	}
+
+	existing, err := findPost(s, feed, item)
+	if err == sql.ErrNoRows {
+		post, err := s.DB.CreatePost(context.Background(), database.CreatePostParams{
+			ID: uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			Title: item.Title,
+			Url: item.Link,
+			Description: nullString(item.Description),
+			PublishedAt: pubDate,
+			FeedID: feed.ID,
+			Guid: nullString(item.GUID),
+			Content: nullString(item.Content),
+			ContentHash: hash,
+			Simhash: Simhash(item.Title, item.Description),
+			Author: itemAuthor(item),
+			Categories: itemCategories(item),
+		})
+		if err != nil {
+			if isUniqueViolation(err) {
+				return database.Post{}, false, nil
+			}
+			return database.Post{}, false, err
+		}
+
+		// the post is stored either way, it is just shown on its own
+		if err := clusterPost(s, post); err != nil {
+			s.Logger.Warn("couldn't cluster post", "feed_id", feed.ID, "post_id", post.ID, "error", err)
+		}
+		return post, true, nil
+	}
+	if err != nil {
+		return database.Post{}, false, err
+	}
+
+	// the url already belongs to a post of another feed
+	if existing.FeedID != feed.ID {
+		err := s.DB.AddPostSighting(context.Background(), database.AddPostSightingParams{
+			PostID: existing.ID,
+			FeedID: feed.ID,
+			SeenAt: time.Now().UTC(),
+		})
+		return existing, false, err
+	}
+
+	linkChanged := existing.Url != item.Link
+	if existing.ContentHash == hash && !linkChanged {
+		return existing, false, nil
+	}
+
+	params := database.UpdatePostContentParams{
+		ID: existing.ID,
+		Title: item.Title,
+		Description: nullString(item.Description),
+		Content: nullString(item.Content),
+		ContentHash: hash,
+		Guid: nullString(item.GUID),
+		UpdatedAt: time.Now().UTC(),
+		Url: item.Link,
+	}
+
+	// a moved link alone isn't an edit, and posts stored before revisions were tracked
+	// have no hash yet, so only a changed title or description counts as an edit for them
+	if existing.ContentHash == hash || (existing.ContentHash == "" && existing.Title == item.Title && existing.Description.String == item.Description) {
+		post, err := updatePost(s, existing, params)
+		return post, false, err
+	}
+
+	_, err = s.DB.CreatePostRevision(context.Background(), database.CreatePostRevisionParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		PostID: existing.ID,
+		Title: existing.Title,
+		Description: existing.Description,
+		Content: existing.Content,
+		ContentHash: existing.ContentHash,
+	})
+	if err != nil {
+		return database.Post{}, false, err
+	}
+
+	params.RevisedAt = sql.NullTime{Time: time.Now().UTC(), Valid: true}
+	post, err := updatePost(s, existing, params)
+	if err != nil {
+		return database.Post{}, false, err
+	}
+
+	s.Logger.Info("post revised", "feed_id", feed.ID, "post_id", existing.ID, "url", existing.Url)
+	return post, false, nil
+}
+
+// updatePost stores the new version of existing, keeping its old link when the new one
+// already belongs to another post
+func updatePost(s *State, existing database.Post, params database.UpdatePostContentParams) (database.Post, error) {
+	post, err := s.DB.UpdatePostContent(context.Background(), params)
+	if err != nil && isUniqueViolation(err) && params.Url != existing.Url {
+		s.Logger.Warn("post link is taken by another post, keeping the old one", "post_id", existing.ID, "url", params.Url)
+		params.Url = existing.Url
+		post, err = s.DB.UpdatePostContent(context.Background(), params)
+	}
+	return post, err
+}
+
+// isUniqueViolation tells whether err comes from a unique constraint, in Postgres or SQLite
+func isUniqueViolation(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "duplicate key") || strings.Contains(msg, "UNIQUE constraint failed")
+}
+
+func findPost(s *State, feed database.Feed, item rss.RSSItem) (database.Post, error) {
+	if item.GUID != "" {
+		post, err := s.DB.GetPostByFeedGUID(context.Background(), database.GetPostByFeedGUIDParams{
+			FeedID: feed.ID,
+			Guid: nullString(item.GUID),
+		})
+		if err != sql.ErrNoRows {
+			return post, err
+		}
+	}
+
+	return FindPostByURL(s, item.Link)
+}
+
+// itemAuthor prefers dc:creator, the plain author of RSS 2.0 is often just an email address
+func itemAuthor(item rss.RSSItem) string {
+	if creator := strings.TrimSpace(item.Creator); creator != "" {
+		return creator
+	}
+	return strings.TrimSpace(item.Author)
+}
+
+// itemCategories keeps the categories of item one per line
+func itemCategories(item rss.RSSItem) string {
+	var categories []string
+	for _, category := range item.Categories {
+		if category = strings.Join(strings.Fields(category), " "); category != "" {
+			categories = append(categories, category)
+		}
+	}
+	return strings.Join(categories, "\n")
+}
+
+func contentHash(title, description, content string) string {
+	sum := sha256.Sum256([]byte(title + "\x00" + description + "\x00" + content))
+	return hex.EncodeToString(sum[:])
+}
+
+func nullString(str string) sql.NullString {
+	if str == "" {
+		return sql.NullString{}
+	}
+	return sql.NullString{String: str, Valid: true}
+}
+
+// maxDiffWords keeps the word diff cheap, longer texts are shown as a plain replacement
+const maxDiffWords = 2000
+
+// DiffWords returns new with removed words marked as [-word-] and added ones as {+word+},
+// the same way git diff --word-diff shows them
+func DiffWords(old, new string) string {
+	a := strings.Fields(old)
+	b := strings.Fields(new)
+
+	if len(a) > maxDiffWords || len(b) > maxDiffWords {
+		return "[-" + old + "-]{+" + new + "+}"
+	}
+
+	// lcs[i][j] is the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var out []string
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			out = append(out, a[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			out = append(out, "[-"+a[i]+"-]")
+			i++
+		default:
+			out = append(out, "{+"+b[j]+"+}")
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		out = append(out, "[-"+a[i]+"-]")
+	}
+	for ; j < len(b); j++ {
+		out = append(out, "{+"+b[j]+"+}")
+	}
+
+	return strings.Join(out, " ")
+}
@@ -0,0 +1,36 @@
+package service_test
+
+import (
+	"strings"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func TestDiffWords(t *testing.T) {
+	tests := []struct {
+		old, new	string
+		want		string
+	}{
+		{old: "", new: "", want: ""},
+		{old: "same text", new: "same text", want: "same text"},
+		{old: "", new: "new words", want: "{+new+} {+words+}"},
+		{old: "old words", new: "", want: "[-old-] [-words-]"},
+		{old: "version 1 is out", new: "version 2 is out", want: "version [-1-] {+2+} is out"},
+		{old: "a b c", new: "a c", want: "a [-b-] c"},
+		{old: "a c", new: "a b c", want: "a {+b+} c"},
+		{old: "fixed  the\ttypo", new: "fixed the typo now", want: "fixed the typo {+now+}"},
+	}
+	for _, test := range tests {
+		if got := service.DiffWords(test.old, test.new); got != test.want {
+			t.Errorf("DiffWords(%q, %q) = %q, want %q", test.old, test.new, got, test.want)
+		}
+	}
+}
+
+func TestDiffWordsOfLongTexts(t *testing.T) {
+	old := strings.Repeat("word ", 3000)
+	if got, want := service.DiffWords(old, "short"), "[-"+old+"-]{+short+}"; got != want {
+		t.Errorf("DiffWords of a long text isn't a plain replacement: %.40q", got)
+	}
+}
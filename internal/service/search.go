@@ -0,0 +1,224 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+var ErrEmptySearch = errors.New("search query is empty")
+
+// ParseSearchQuery turns what the user typed into a to_tsquery expression.
+//
+// Supported syntax:
+//	go generics      both words (AND is implied, and may also be written out)
+//	go OR rust       either word
+//	-java, NOT java  without the word
+//	"error handling" the exact phrase
+//	gener*           any word starting with "gener"
+//	(a OR b) c       grouping
+func ParseSearchQuery(query string) (string, error) {
+	tokens, err := tokenizeSearch(query)
+	if err != nil {
+		return "", err
+	}
+
+	p := &searchParser{tokens: tokens}
+	expr, err := p.parseOr()
+	if err != nil {
+		return "", err
+	}
+	if p.pos < len(p.tokens) {
+		return "", fmt.Errorf("unexpected %q in search query", p.tokens[p.pos].text)
+	}
+	if expr == "" {
+		return "", ErrEmptySearch
+	}
+	return expr, nil
+}
+
+type searchTokenKind int
+
+const (
+	searchWord searchTokenKind = iota
+	searchPrefix
+	searchPhrase
+	searchAnd
+	searchOr
+	searchNot
+	searchOpen
+	searchClose
+)
+
+type searchToken struct {
+	kind	searchTokenKind
+	text	string
+}
+
+func tokenizeSearch(query string) ([]searchToken, error) {
+	var tokens []searchToken
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: searchOpen, text: "("})
+			i++
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: searchClose, text: ")"})
+			i++
+		case r == '-' || r == '!':
+			tokens = append(tokens, searchToken{kind: searchNot, text: string(r)})
+			i++
+		case r == '"':
+			end := i + 1
+			for end < len(runes) && runes[end] != '"' {
+				end++
+			}
+			if end == len(runes) {
+				return nil, errors.New("unterminated phrase in search query")
+			}
+			phrase := strings.TrimSpace(string(runes[i+1 : end]))
+			if phrase != "" {
+				tokens = append(tokens, searchToken{kind: searchPhrase, text: phrase})
+			}
+			i = end + 1
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()\"", runes[end]) {
+				end++
+			}
+			word := string(runes[i:end])
+			i = end
+
+			switch word {
+			case "AND", "&":
+				tokens = append(tokens, searchToken{kind: searchAnd, text: word})
+			case "OR", "|":
+				tokens = append(tokens, searchToken{kind: searchOr, text: word})
+			case "NOT":
+				tokens = append(tokens, searchToken{kind: searchNot, text: word})
+			default:
+				if stem, ok := strings.CutSuffix(word, "*"); ok {
+					if stem = strings.TrimRight(stem, "*"); stem != "" {
+						tokens = append(tokens, searchToken{kind: searchPrefix, text: stem})
+					}
+					continue
+				}
+				tokens = append(tokens, searchToken{kind: searchWord, text: word})
+			}
+		}
+	}
+
+	return tokens, nil
+}
+
+type searchParser struct {
+	tokens	[]searchToken
+	pos		int
+}
+
+func (p *searchParser) peek() (searchToken, bool) {
+	if p.pos >= len(p.tokens) {
+		return searchToken{}, false
+	}
+	return p.tokens[p.pos], true
+}
+
+func (p *searchParser) parseOr() (string, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return "", err
+	}
+
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind != searchOr {
+			return left, nil
+		}
+		p.pos++
+
+		right, err := p.parseAnd()
+		if err != nil {
+			return "", err
+		}
+		left = joinSearch(left, right, " | ")
+	}
+}
+
+func (p *searchParser) parseAnd() (string, error) {
+	var expr string
+	for {
+		tok, ok := p.peek()
+		if !ok || tok.kind == searchOr || tok.kind == searchClose {
+			return expr, nil
+		}
+		if tok.kind == searchAnd {
+			p.pos++
+			continue
+		}
+
+		term, err := p.parseUnary()
+		if err != nil {
+			return "", err
+		}
+		expr = joinSearch(expr, term, " & ")
+	}
+}
+
+func (p *searchParser) parseUnary() (string, error) {
+	tok, _ := p.peek()
+	p.pos++
+
+	switch tok.kind {
+	case searchNot:
+		if _, ok := p.peek(); !ok {
+			return "", nil
+		}
+		term, err := p.parseUnary()
+		if err != nil || term == "" {
+			return "", err
+		}
+		return "!" + term, nil
+	case searchOpen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return "", err
+		}
+		if closing, ok := p.peek(); !ok || closing.kind != searchClose {
+			return "", errors.New("missing ) in search query")
+		}
+		p.pos++
+		if expr == "" {
+			return "", nil
+		}
+		return "(" + expr + ")", nil
+	case searchClose:
+		return "", errors.New("unexpected ) in search query")
+	case searchPrefix:
+		return quoteLexeme(tok.text) + ":*", nil
+	default:
+		return quoteLexeme(tok.text), nil
+	}
+}
+
+func joinSearch(left, right, op string) string {
+	switch {
+	case left == "":
+		return right
+	case right == "":
+		return left
+	}
+	return left + op + right
+}
+
+// quoteLexeme quotes text for to_tsquery, quoted text of several words becomes a phrase
+func quoteLexeme(text string) string {
+	text = strings.ReplaceAll(text, `\`, `\\`)
+	text = strings.ReplaceAll(text, "'", "''")
+	return "'" + text + "'"
+}
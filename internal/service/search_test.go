@@ -0,0 +1,65 @@
+package service_test
+
+import (
+	"errors"
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func TestParseSearchQuery(t *testing.T) {
+	tests := []struct {
+		query	string
+		want	string
+	}{
+		{query: "go", want: "'go'"},
+		{query: "go generics", want: "'go' & 'generics'"},
+		{query: "go AND generics", want: "'go' & 'generics'"},
+		{query: "go OR rust", want: "'go' | 'rust'"},
+		{query: "go rust OR zig", want: "'go' & 'rust' | 'zig'"},
+		{query: "-java", want: "!'java'"},
+		{query: "go NOT java", want: "'go' & !'java'"},
+		{query: `"error handling"`, want: "'error handling'"},
+		{query: "gener*", want: "'gener':*"},
+		{query: "(go OR rust) tips", want: "('go' | 'rust') & 'tips'"},
+		{query: "it's", want: "'it''s'"},
+		{query: `back\slash`, want: `'back\\slash'`},
+		{query: "go -", want: "'go'"},
+		{query: "go ()", want: "'go'"},
+	}
+	for _, test := range tests {
+		got, err := service.ParseSearchQuery(test.query)
+		if err != nil {
+			t.Errorf("ParseSearchQuery(%q): %v", test.query, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("ParseSearchQuery(%q) = %q, want %q", test.query, got, test.want)
+		}
+	}
+}
+
+func TestParseSearchQueryErrors(t *testing.T) {
+	tests := []struct {
+		query	string
+		empty	bool
+	}{
+		{query: "", empty: true},
+		{query: "   ", empty: true},
+		{query: `""`, empty: true},
+		{query: "*", empty: true},
+		{query: `"error handling`},
+		{query: "(go OR rust"},
+		{query: "go)"},
+	}
+	for _, test := range tests {
+		_, err := service.ParseSearchQuery(test.query)
+		if err == nil {
+			t.Errorf("ParseSearchQuery(%q) succeeded", test.query)
+			continue
+		}
+		if empty := errors.Is(err, service.ErrEmptySearch); empty != test.empty {
+			t.Errorf("ParseSearchQuery(%q) = %v, is ErrEmptySearch %v, want %v", test.query, err, empty, test.empty)
+		}
+	}
+}
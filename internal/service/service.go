@@ -1,18 +1,66 @@
 package service
 
 import (
-	"grysha11/BlogAggregator/internal/database"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"time"
 	"grysha11/BlogAggregator/internal/config"
+	"grysha11/BlogAggregator/internal/metrics"
+	"grysha11/BlogAggregator/internal/rss"
+	"grysha11/BlogAggregator/internal/storage"
 )
 
 type State struct {
-	DB 		*database.Queries
-	Config	*config.Config
+	DB 			Store
+	Conn		*sql.DB
+	Backend		storage.Backend
+	Config		*config.Config
+	Metrics		*metrics.Metrics
+	Logger		*slog.Logger
+	Fetcher		*rss.Client
+	// Hooks delivers hooks in the background, Close it before exiting
+	Hooks		*HookQueue
+	// Transact runs fn inside a database transaction, it is nil for stores without them
+	Transact	func(fn func(Store) error) error
 }
 
-func New(db *database.Queries, config *config.Config) *State {
-	return &State{
+func New(db Store, config *config.Config) *State {
+	s := &State{
 		DB: db,
 		Config: config,
+		Logger: slog.Default(),
+		Fetcher: rss.NewClient(rss.Options{HostInterval: rss.DefaultHostInterval}),
 	}
+	s.Hooks = newHookQueue(s)
+	return s
+}
+
+// NewFetcher builds the feed client from the politeness settings in the config
+func NewFetcher(cfg *config.Config) (*rss.Client, error) {
+	interval := rss.DefaultHostInterval
+	if cfg.HostInterval != "" {
+		d, err := time.ParseDuration(cfg.HostInterval)
+		if err != nil {
+			return nil, fmt.Errorf("invalid host_interval in config: %v", err)
+		}
+		interval = d
+	}
+
+	return rss.NewClient(rss.Options{
+		UserAgent: cfg.UserAgent,
+		ContactURL: cfg.ContactURL,
+		HostInterval: interval,
+		MaxPerHost: cfg.MaxPerHost,
+		CheckRobots: cfg.CheckRobots,
+	}), nil
+}
+
+// InTx runs fn against a Store whose changes are kept together or not at all.
+// The in-memory store has no transactions, fn runs on s.DB directly there.
+func (s *State) InTx(fn func(Store) error) error {
+	if s.Transact == nil {
+		return fn(s.DB)
+	}
+	return s.Transact(fn)
 }
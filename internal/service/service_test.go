@@ -0,0 +1,8 @@
+package service_test
+
+import (
+	"grysha11/BlogAggregator/internal/memory"
+	"grysha11/BlogAggregator/internal/service"
+)
+
+var _ service.Store = (*memory.Store)(nil)
@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+
+	"grysha11/BlogAggregator/internal/database"
+
+	"github.com/google/uuid"
+)
+
+// Store is everything gator keeps. *database.Queries implements it on top of Postgres or SQLite,
+// memory.Store keeps it in maps for tests and the --ephemeral demo.
+type Store interface {
+	UserStore
+	FeedStore
+	FollowStore
+	PostStore
+	HookStore
+	MuteStore
+}
+
+var _ Store = (*database.Queries)(nil)
+
+// UserStore keeps the accounts
+type UserStore interface {
+	CreateUser(ctx context.Context, arg database.CreateUserParams) (database.User, error)
+	DeleteUser(ctx context.Context, id uuid.UUID) error
+	DeleteUsers(ctx context.Context) error
+	GetAllUsers(ctx context.Context) ([]database.User, error)
+	GetUserByID(ctx context.Context, id uuid.UUID) (database.User, error)
+	GetUserByName(ctx context.Context, name string) (database.User, error)
+	RenameUser(ctx context.Context, arg database.RenameUserParams) (database.User, error)
+	SetUserAdmin(ctx context.Context, arg database.SetUserAdminParams) (database.User, error)
+	SetUserTimezone(ctx context.Context, arg database.SetUserTimezoneParams) (database.User, error)
+}
+
+// FeedStore keeps the feeds and when they were fetched
+type FeedStore interface {
+	CreateFeed(ctx context.Context, arg database.CreateFeedParams) (database.Feed, error)
+	DeleteFeed(ctx context.Context, id uuid.UUID) error
+	DeleteFeeds(ctx context.Context) error
+	DeleteOrphanedFeeds(ctx context.Context, ids []uuid.UUID) (int64, error)
+	GetAllFeeds(ctx context.Context) ([]database.Feed, error)
+	GetFeedByURL(ctx context.Context, url string) (database.Feed, error)
+	GetFeedsForUser(ctx context.Context, userID uuid.UUID) ([]database.Feed, error)
+	GetNextFeedToFetch(ctx context.Context) (database.Feed, error)
+	GetOwnedFeedIDs(ctx context.Context, userID uuid.NullUUID) ([]uuid.UUID, error)
+	HandOffOwnedFeeds(ctx context.Context, arg database.HandOffOwnedFeedsParams) (int64, error)
+	MarkFeedFetched(ctx context.Context, arg database.MarkFeedFetchedParams) (database.Feed, error)
+	RenameFeed(ctx context.Context, arg database.RenameFeedParams) (database.Feed, error)
+	SetFeedOwner(ctx context.Context, arg database.SetFeedOwnerParams) (database.Feed, error)
+	SetFeedRetention(ctx context.Context, arg database.SetFeedRetentionParams) (database.Feed, error)
+	SetFeedURL(ctx context.Context, arg database.SetFeedURLParams) (database.Feed, error)
+}
+
+// FollowStore keeps who follows which feed and the folders follows are sorted into
+type FollowStore interface {
+	CreateFeedFollow(ctx context.Context, arg database.CreateFeedFollowParams) (database.CreateFeedFollowRow, error)
+	DeleteFeedFollowByUrl(ctx context.Context, arg database.DeleteFeedFollowByUrlParams) error
+	DeleteFeedFollows(ctx context.Context) error
+	GetFeedFollowByURL(ctx context.Context, arg database.GetFeedFollowByURLParams) (database.FeedFollow, error)
+	GetFeedFollowsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFeedFollowsForUserRow, error)
+	SetFeedFollowName(ctx context.Context, arg database.SetFeedFollowNameParams) (database.FeedFollow, error)
+	SetFeedFollowNotes(ctx context.Context, arg database.SetFeedFollowNotesParams) (database.FeedFollow, error)
+	AddFollowToFolder(ctx context.Context, arg database.AddFollowToFolderParams) error
+	CreateFolder(ctx context.Context, arg database.CreateFolderParams) (database.Folder, error)
+	DeleteFolder(ctx context.Context, id uuid.UUID) error
+	GetFolderByName(ctx context.Context, arg database.GetFolderByNameParams) (database.Folder, error)
+	GetFoldersForUser(ctx context.Context, userID uuid.UUID) ([]database.Folder, error)
+	GetFollowFoldersForUser(ctx context.Context, userID uuid.UUID) ([]database.GetFollowFoldersForUserRow, error)
+	RemoveFollowFromAllFolders(ctx context.Context, feedFollowID uuid.UUID) error
+	RemoveFollowFromFolder(ctx context.Context, arg database.RemoveFollowFromFolderParams) (int64, error)
+	RenameFolder(ctx context.Context, arg database.RenameFolderParams) (database.Folder, error)
+}
+
+// PostStore keeps the posts with their revisions and what users did with them
+type PostStore interface {
+	GetReadPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetReadPostsForUserRow, error)
+	MarkAllPostsRead(ctx context.Context, arg database.MarkAllPostsReadParams) (int64, error)
+	MarkPostRead(ctx context.Context, arg database.MarkPostReadParams) error
+	MarkPostUnread(ctx context.Context, arg database.MarkPostUnreadParams) error
+	CreatePostRevision(ctx context.Context, arg database.CreatePostRevisionParams) (database.PostRevision, error)
+	GetLatestPostRevision(ctx context.Context, postID uuid.UUID) (database.PostRevision, error)
+	GetStarredPostsForUser(ctx context.Context, userID uuid.UUID) ([]database.GetStarredPostsForUserRow, error)
+	StarPost(ctx context.Context, arg database.StarPostParams) error
+	UnstarPost(ctx context.Context, arg database.UnstarPostParams) (int64, error)
+	CreatePost(ctx context.Context, arg database.CreatePostParams) (database.Post, error)
+	DeletePostsByIDs(ctx context.Context, ids []uuid.UUID) error
+	GetPostByFeedGUID(ctx context.Context, arg database.GetPostByFeedGUIDParams) (database.Post, error)
+	GetPostByID(ctx context.Context, id uuid.UUID) (database.Post, error)
+	GetPostByURL(ctx context.Context, url string) (database.Post, error)
+	GetLatestPostForFeed(ctx context.Context, feedID uuid.UUID) (database.Post, error)
+	GetPostsForUser(ctx context.Context, arg database.GetPostsForUserParams) ([]database.GetPostsForUserRow, error)
+	GetPrunablePosts(ctx context.Context, arg database.GetPrunablePostsParams) ([]database.Post, error)
+	UpdatePostContent(ctx context.Context, arg database.UpdatePostContentParams) (database.Post, error)
+	AddToReadLater(ctx context.Context, arg database.AddToReadLaterParams) error
+	GetReadLaterForUser(ctx context.Context, userID uuid.UUID) ([]database.GetReadLaterForUserRow, error)
+	RemoveFromReadLater(ctx context.Context, arg database.RemoveFromReadLaterParams) (int64, error)
+	SearchPosts(ctx context.Context, arg database.SearchPostsParams) ([]database.SearchPostsRow, error)
+	AddPostSighting(ctx context.Context, arg database.AddPostSightingParams) error
+	GetClusterCandidates(ctx context.Context, arg database.GetClusterCandidatesParams) ([]database.GetClusterCandidatesRow, error)
+	GetClusterFeedNames(ctx context.Context, arg database.GetClusterFeedNamesParams) ([]string, error)
+	SetPostCluster(ctx context.Context, arg database.SetPostClusterParams) error
+}
+
+// HookStore keeps the hooks and their delivery log
+type HookStore interface {
+	CreateHook(ctx context.Context, arg database.CreateHookParams) (database.Hook, error)
+	CreateHookDelivery(ctx context.Context, arg database.CreateHookDeliveryParams) (database.HookDelivery, error)
+	DeleteHook(ctx context.Context, arg database.DeleteHookParams) (int64, error)
+	GetHookDeliveriesForUser(ctx context.Context, arg database.GetHookDeliveriesForUserParams) ([]database.GetHookDeliveriesForUserRow, error)
+	GetHooksForFeed(ctx context.Context, feedID uuid.UUID) ([]database.Hook, error)
+	GetHooksForUser(ctx context.Context, userID uuid.UUID) ([]database.GetHooksForUserRow, error)
+}
+
+// MuteStore keeps the rules users hide posts with
+type MuteStore interface {
+	CreateMute(ctx context.Context, arg database.CreateMuteParams) (database.Mute, error)
+	DeleteMute(ctx context.Context, arg database.DeleteMuteParams) (int64, error)
+	GetMutesForUser(ctx context.Context, userID uuid.UUID) ([]database.GetMutesForUserRow, error)
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"grysha11/BlogAggregator/internal/database"
+)
+
+// LocalTimezone is what the timezone setting shows when the user hasn't chosen one
+const LocalTimezone = "local"
+
+// Location returns the zone dates are shown to user in, the machine's one unless the user picked another
+func Location(user database.User) *time.Location {
+	if user.Timezone == "" {
+		return time.Local
+	}
+	loc, err := time.LoadLocation(user.Timezone)
+	if err != nil {
+		return time.Local
+	}
+	return loc
+}
+
+// SetTimezone stores the zone user wants to see dates in, "local" goes back to the machine's one
+func SetTimezone(s *State, user database.User, name string) (database.User, error) {
+	if name == LocalTimezone {
+		name = ""
+	} else if _, err := time.LoadLocation(name); err != nil || name == "" || name == "Local" {
+		return database.User{}, fmt.Errorf("unknown timezone: %v (expected a name like Europe/Berlin, UTC or local)", name)
+	}
+
+	return s.DB.SetUserTimezone(context.Background(), database.SetUserTimezoneParams{
+		ID: user.ID,
+		Timezone: name,
+		UpdatedAt: time.Now().UTC(),
+	})
+}
+
+// FormatTime shows t in loc together with how long ago it was, like "2006-01-02 15:04 CET (3h ago)"
+func FormatTime(t time.Time, loc *time.Location) string {
+	return fmt.Sprintf("%v (%v)", t.In(loc).Format("2006-01-02 15:04 MST"), Ago(t, time.Now()))
+}
+
+// Ago tells how far t is from now in the largest fitting unit, like "3h ago" or "in 5m"
+func Ago(t, now time.Time) string {
+	d := now.Sub(t)
+	future := d < 0
+	if future {
+		d = -d
+	}
+
+	var amount string
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		amount = fmt.Sprintf("%vm", int(d/time.Minute))
+	case d < 24*time.Hour:
+		amount = fmt.Sprintf("%vh", int(d/time.Hour))
+	case d < 30*24*time.Hour:
+		amount = fmt.Sprintf("%vd", int(d/(24*time.Hour)))
+	case d < 365*24*time.Hour:
+		amount = fmt.Sprintf("%vmo", int(d/(30*24*time.Hour)))
+	default:
+		amount = fmt.Sprintf("%vy", int(d/(365*24*time.Hour)))
+	}
+
+	if future {
+		return "in " + amount
+	}
+	return amount + " ago"
+}
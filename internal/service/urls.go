@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net"
+	"net/url"
+	"path"
+	"strings"
+
+	"grysha11/BlogAggregator/internal/database"
+)
+
+// trackingParams are query parameters which only tell where a click came from
+var trackingParams = map[string]bool{
+	"fbclid": true,
+	"gclid": true,
+	"gclsrc": true,
+	"dclid": true,
+	"msclkid": true,
+	"yclid": true,
+	"igshid": true,
+	"mc_cid": true,
+	"mc_eid": true,
+	"_hsenc": true,
+	"_hsmi": true,
+	"mkt_tok": true,
+}
+
+var defaultPorts = map[string]string{
+	"http": "80",
+	"https": "443",
+}
+
+// CanonicalURL brings a feed or post link to the form it is stored in: lowercase host,
+// no default port, fragment, tracking parameters, dot segments or trailing slash.
+// The scheme is kept since some sites only answer on one of them, lookups try both.
+func CanonicalURL(raw string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil {
+		return "", fmt.Errorf("invalid url %q: %v", raw, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("invalid url %q: only http and https are supported", raw)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("invalid url %q: host is missing", raw)
+	}
+
+	host := strings.ToLower(u.Hostname())
+	port := u.Port()
+	if port == defaultPorts[u.Scheme] {
+		port = ""
+	}
+	switch {
+	case port != "":
+		u.Host = net.JoinHostPort(host, port)
+	case strings.Contains(host, ":"):
+		u.Host = "[" + host + "]"
+	default:
+		u.Host = host
+	}
+
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	if u.RawQuery != "" {
+		var kept []string
+		for _, pair := range strings.Split(u.RawQuery, "&") {
+			if pair == "" {
+				continue
+			}
+			key, _, _ := strings.Cut(pair, "=")
+			if name, err := url.QueryUnescape(key); err == nil {
+				name = strings.ToLower(name)
+				if strings.HasPrefix(name, "utm_") || trackingParams[name] {
+					continue
+				}
+			}
+			kept = append(kept, pair)
+		}
+		u.RawQuery = strings.Join(kept, "&")
+	}
+	u.ForceQuery = false
+
+	// dot segments are resolved like a browser does, on the escaped path so an escaped "/" isn't one.
+	// path.Clean drops the trailing slash too but keeps a lone "/", which goes here with the others
+	if escaped := u.EscapedPath(); escaped != "" {
+		cleaned := strings.TrimRight(path.Clean(escaped), "/")
+		unescaped, err := url.PathUnescape(cleaned)
+		if err != nil {
+			return "", fmt.Errorf("invalid url %q: %v", raw, err)
+		}
+		u.Path, u.RawPath = unescaped, cleaned
+	}
+
+	return u.String(), nil
+}
+
+// urlVariants returns the links a stored feed or post could be known by:
+// the canonical one, the same one on the other scheme and the link as it was given
+func urlVariants(raw string) []string {
+	canonical, err := CanonicalURL(raw)
+	if err != nil {
+		return []string{raw}
+	}
+
+	variants := []string{canonical}
+	if rest, ok := strings.CutPrefix(canonical, "https://"); ok {
+		variants = append(variants, "http://"+rest)
+	} else if rest, ok := strings.CutPrefix(canonical, "http://"); ok {
+		variants = append(variants, "https://"+rest)
+	}
+	if raw != canonical {
+		variants = append(variants, raw)
+	}
+	return variants
+}
+
+// FindFeed looks a feed up by any spelling of its url, sql.ErrNoRows if there is none
+func FindFeed(s *State, feedURL string) (database.Feed, error) {
+	for _, variant := range urlVariants(feedURL) {
+		feed, err := s.DB.GetFeedByURL(context.Background(), variant)
+		if err != sql.ErrNoRows {
+			return feed, err
+		}
+	}
+	return database.Feed{}, sql.ErrNoRows
+}
+
+// FindPostByURL looks a post up by any spelling of its link, sql.ErrNoRows if there is none
+func FindPostByURL(s *State, postURL string) (database.Post, error) {
+	for _, variant := range urlVariants(postURL) {
+		post, err := s.DB.GetPostByURL(context.Background(), variant)
+		if err != sql.ErrNoRows {
+			return post, err
+		}
+	}
+	return database.Post{}, sql.ErrNoRows
+}
@@ -0,0 +1,56 @@
+package service_test
+
+import (
+	"testing"
+
+	"grysha11/BlogAggregator/internal/service"
+)
+
+func TestCanonicalURL(t *testing.T) {
+	tests := []struct {
+		name	string
+		raw		string
+		want	string
+	}{
+		{name: "already canonical", raw: "https://example.com/feed.xml", want: "https://example.com/feed.xml"},
+		{name: "spaces around", raw: "  https://example.com/feed.xml\n", want: "https://example.com/feed.xml"},
+		{name: "host case", raw: "https://Blog.Example.COM/Feed.xml", want: "https://blog.example.com/Feed.xml"},
+		{name: "http default port", raw: "http://example.com:80/feed", want: "http://example.com/feed"},
+		{name: "https default port", raw: "https://example.com:443/feed", want: "https://example.com/feed"},
+		{name: "other port", raw: "https://example.com:8443/feed", want: "https://example.com:8443/feed"},
+		{name: "port of the other scheme", raw: "http://example.com:443/feed", want: "http://example.com:443/feed"},
+		{name: "ipv6 host", raw: "http://[::1]:80/feed", want: "http://[::1]/feed"},
+		{name: "utm parameters", raw: "https://example.com/post?utm_source=rss&utm_medium=feed", want: "https://example.com/post"},
+		{name: "tracking parameters", raw: "https://example.com/post?id=7&fbclid=abc&GCLID=def", want: "https://example.com/post?id=7"},
+		{name: "other parameters kept in order", raw: "https://example.com/post?b=2&utm_campaign=x&a=1", want: "https://example.com/post?b=2&a=1"},
+		{name: "empty query", raw: "https://example.com/post?", want: "https://example.com/post"},
+		{name: "fragment", raw: "https://example.com/post#comments", want: "https://example.com/post"},
+		{name: "trailing slash", raw: "https://example.com/blog/", want: "https://example.com/blog"},
+		{name: "trailing slashes", raw: "https://example.com/blog//", want: "https://example.com/blog"},
+		{name: "root", raw: "https://example.com/", want: "https://example.com"},
+		{name: "dot segment", raw: "https://example.com/blog/./feed.xml", want: "https://example.com/blog/feed.xml"},
+		{name: "parent segment", raw: "https://example.com/blog/../feed.xml", want: "https://example.com/feed.xml"},
+		{name: "parent of the root", raw: "http://localhost/../feed2.xml", want: "http://localhost/feed2.xml"},
+		{name: "parent segment at the end", raw: "https://example.com/blog/posts/..", want: "https://example.com/blog"},
+		{name: "escaped slash", raw: "https://example.com/a%2Fb/../c/", want: "https://example.com/c"},
+		{name: "escaped path", raw: "https://example.com/a%20b/./c/", want: "https://example.com/a%20b/c"},
+	}
+	for _, test := range tests {
+		got, err := service.CanonicalURL(test.raw)
+		if err != nil {
+			t.Errorf("%v: CanonicalURL(%q): %v", test.name, test.raw, err)
+			continue
+		}
+		if got != test.want {
+			t.Errorf("%v: CanonicalURL(%q) = %q, want %q", test.name, test.raw, got, test.want)
+		}
+	}
+}
+
+func TestCanonicalURLRejects(t *testing.T) {
+	for _, raw := range []string{"", "example.com/feed", "ftp://example.com/feed", "https://", "http://exa mple.com/"} {
+		if got, err := service.CanonicalURL(raw); err == nil {
+			t.Errorf("CanonicalURL(%q) = %q, want an error", raw, got)
+		}
+	}
+}
@@ -0,0 +1,155 @@
+// Package servicetest has the helpers the service and cli tests share
+package servicetest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"grysha11/BlogAggregator/internal/config"
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/memory"
+	"grysha11/BlogAggregator/internal/rss"
+	"grysha11/BlogAggregator/internal/service"
+
+	"github.com/google/uuid"
+)
+
+// NewState returns a state on an empty in-memory store which leaves the config file alone
+func NewState(t *testing.T) *service.State {
+	t.Helper()
+
+	s := service.New(memory.New(), &config.Config{Ephemeral: true})
+	s.Logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+	s.Fetcher = rss.NewClient(rss.Options{HostInterval: time.Millisecond})
+	t.Cleanup(s.Hooks.Close)
+	return s
+}
+
+type Item struct {
+	Title		string
+	Link		string
+	GUID		string
+	Description	string
+	PubDate		time.Time
+}
+
+// FeedServer serves an RSS feed made of whatever items were set last
+type FeedServer struct {
+	*httptest.Server
+	mu		sync.Mutex
+	items	[]Item
+}
+
+func NewFeedServer(t *testing.T, items ...Item) *FeedServer {
+	t.Helper()
+
+	f := &FeedServer{items: items}
+	f.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+
+		var b strings.Builder
+		b.WriteString(`<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><link>http://example.com</link><description>test feed</description>`)
+		for _, item := range f.items {
+			fmt.Fprintf(&b, "<item><title>%v</title><link>%v</link>", item.Title, item.Link)
+			if item.GUID != "" {
+				fmt.Fprintf(&b, "<guid>%v</guid>", item.GUID)
+			}
+			fmt.Fprintf(&b, "<description>%v</description><pubDate>%v</pubDate></item>", item.Description, item.PubDate.Format(time.RFC1123Z))
+		}
+		b.WriteString("</channel></rss>")
+
+		w.Header().Set("Content-Type", "application/rss+xml")
+		io.WriteString(w, b.String())
+	}))
+	t.Cleanup(f.Close)
+	return f
+}
+
+func (f *FeedServer) SetItems(items ...Item) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.items = items
+}
+
+func CreateUser(t *testing.T, s *service.State, name string, admin bool) database.User {
+	t.Helper()
+
+	user, err := s.DB.CreateUser(context.Background(), database.CreateUserParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name: name,
+		IsAdmin: admin,
+	})
+	if err != nil {
+		t.Fatalf("couldn't create user %v: %v", name, err)
+	}
+	return user
+}
+
+// CreateFeed adds a feed owned and followed by owner
+func CreateFeed(t *testing.T, s *service.State, owner database.User, name, url string) database.Feed {
+	t.Helper()
+
+	feed, err := s.DB.CreateFeed(context.Background(), database.CreateFeedParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		Name: name,
+		Url: url,
+		UserID: uuid.NullUUID{UUID: owner.ID, Valid: true},
+	})
+	if err != nil {
+		t.Fatalf("couldn't create feed %v: %v", name, err)
+	}
+	Follow(t, s, owner, feed)
+	return feed
+}
+
+func Follow(t *testing.T, s *service.State, user database.User, feed database.Feed) {
+	t.Helper()
+
+	_, err := s.DB.CreateFeedFollow(context.Background(), database.CreateFeedFollowParams{
+		ID: uuid.New(),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+		UserID: user.ID,
+		FeedID: feed.ID,
+	})
+	if err != nil {
+		t.Fatalf("couldn't follow feed %v: %v", feed.Name, err)
+	}
+}
+
+// CreatePosts adds n posts to feed, an hour apart with the newest last
+func CreatePosts(t *testing.T, s *service.State, feed database.Feed, n int) []database.Post {
+	t.Helper()
+
+	var posts []database.Post
+	start := time.Now().UTC().Add(-time.Duration(n) * time.Hour)
+	for i := range n {
+		post, err := s.DB.CreatePost(context.Background(), database.CreatePostParams{
+			ID: uuid.New(),
+			CreatedAt: time.Now().UTC(),
+			UpdatedAt: time.Now().UTC(),
+			Title: fmt.Sprintf("Post %v", i),
+			Url: fmt.Sprintf("http://example.com/%v/%v", feed.Name, i),
+			PublishedAt: start.Add(time.Duration(i) * time.Hour),
+			FeedID: feed.ID,
+		})
+		if err != nil {
+			t.Fatalf("CreatePost: %v", err)
+		}
+		posts = append(posts, post)
+	}
+	return posts
+}
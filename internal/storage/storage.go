@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"grysha11/BlogAggregator/internal/database"
+	"grysha11/BlogAggregator/internal/database/sqlite"
+
+	_ "github.com/lib/pq"
+)
+
+type Backend string
+
+const (
+	Postgres	Backend = "postgres"
+	SQLite		Backend = "sqlite"
+)
+
+type DB struct {
+	Conn	*sql.DB
+	Queries	*database.Queries
+	Backend	Backend
+	// sqlite translates the queries when Backend is SQLite
+	sqlite	*sqlite.DB
+}
+
+// Open connects to the database dbURL points at, the scheme picks the backend:
+// sqlite:///path/to/gator.db or sqlite:~/gator.db use a local file, anything else goes to Postgres
+func Open(dbURL string) (*DB, error) {
+	path, ok := sqlitePath(dbURL)
+	if !ok {
+		conn, err := sql.Open("postgres", dbURL)
+		if err != nil {
+			return nil, err
+		}
+		return &DB{
+			Conn: conn,
+			Queries: database.New(conn),
+			Backend: Postgres,
+		}, nil
+	}
+
+	if dir := filepath.Dir(path); dir != "" {
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			return nil, err
+		}
+	}
+
+	conn, err := sqlite.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	db, err := sqlite.New(conn)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return &DB{
+		Conn: conn,
+		Queries: database.New(db),
+		Backend: SQLite,
+		sqlite: db,
+	}, nil
+}
+
+// InTx runs fn with queries inside one transaction, which is committed when fn returns nil
+// and rolled back otherwise
+func (db *DB) InTx(ctx context.Context, fn func(*database.Queries) error) error {
+	tx, err := db.Conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+
+	queries := db.Queries.WithTx(tx)
+	if db.sqlite != nil {
+		queries = database.New(db.sqlite.WithTx(tx))
+	}
+	if err := fn(queries); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+func sqlitePath(dbURL string) (string, bool) {
+	path, ok := strings.CutPrefix(dbURL, "sqlite://")
+	if !ok {
+		path, ok = strings.CutPrefix(dbURL, "sqlite:")
+	}
+	if !ok || path == "" {
+		return "", false
+	}
+
+	if rest, ok := strings.CutPrefix(path, "~/"); ok {
+		if home, err := os.UserHomeDir(); err == nil {
+			path = filepath.Join(home, rest)
+		}
+	}
+	return path, true
+}
@@ -1,13 +1,33 @@
 package ui
 
 import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+	"grysha11/BlogAggregator/internal/database"
 	"grysha11/BlogAggregator/internal/service"
 
 	tea "github.com/charmbracelet/bubbletea"
 )
 
 type Model struct {
-	s	*service.State
+	s		*service.State
+	user	database.User
+	follows	[]database.GetFeedFollowsForUserRow
+	fetched	map[string]sql.NullTime
+	err		error
+}
+
+type followsMsg struct {
+	user	database.User
+	follows	[]database.GetFeedFollowsForUserRow
+	// last fetch time by feed url
+	fetched	map[string]sql.NullTime
+}
+
+type errMsg struct {
+	err	error
 }
 
 func InitialModel(s *service.State) *Model {
@@ -17,7 +37,34 @@ func InitialModel(s *service.State) *Model {
 }
 
 func (m Model) Init() tea.Cmd {
-	return nil
+	return m.loadFollows
+}
+
+// loadFollows gets the current user's feeds, named the way the user named them
+func (m Model) loadFollows() tea.Msg {
+	if m.s.Config.CurrentUsername == "" {
+		return nil
+	}
+
+	user, err := m.s.DB.GetUserByName(context.Background(), m.s.Config.CurrentUsername)
+	if err != nil {
+		return errMsg{err}
+	}
+
+	follows, err := m.s.DB.GetFeedFollowsForUser(context.Background(), user.ID)
+	if err != nil {
+		return errMsg{err}
+	}
+
+	fetched := make(map[string]sql.NullTime)
+	for _, follow := range follows {
+		feed, err := m.s.DB.GetFeedByURL(context.Background(), follow.FeedUrl)
+		if err != nil {
+			return errMsg{err}
+		}
+		fetched[follow.FeedUrl] = feed.LastFetchedAt
+	}
+	return followsMsg{user: user, follows: follows, fetched: fetched}
 }
 
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -25,8 +72,14 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.KeyMsg:
 		switch msg.String() {
 		case "ctrl+c", "q":
-			return m, tea.Quit 
+			return m, tea.Quit
 		}
+	case followsMsg:
+		m.user = msg.user
+		m.follows = msg.follows
+		m.fetched = msg.fetched
+	case errMsg:
+		m.err = msg.err
 	}
 
 	return m, nil
@@ -37,7 +90,22 @@ func (m Model) View() string {
 
 	s += "Connected as: " + m.s.Config.CurrentUsername + "\n\n"
 
+	if m.err != nil {
+		s += fmt.Sprintf("Couldn't load your feeds: %v\n\n", m.err)
+	} else if len(m.follows) > 0 {
+		s += "Your feeds:\n"
+		loc := service.Location(m.user)
+		for _, follow := range m.follows {
+			fetched := "never fetched"
+			if last := m.fetched[follow.FeedUrl]; last.Valid {
+				fetched = fmt.Sprintf("fetched %v at %v", service.Ago(last.Time, time.Now()), last.Time.In(loc).Format("2006-01-02 15:04 MST"))
+			}
+			s += fmt.Sprintf("  * %v (%v unread, %v)\n", follow.FeedName, follow.UnreadCount, fetched)
+		}
+		s += "\n"
+	}
+
 	s += "Press 'q' to quit."
 
 	return s
-}
\ No newline at end of file
+}
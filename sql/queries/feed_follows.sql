@@ -14,7 +14,17 @@ INNER JOIN users ON inserted_feed_follow.user_id = users.id;
 --
 
 -- name: GetFeedFollowsForUser :many
-SELECT feed_follows.*, feeds.name AS feed_name, users.name AS user_name
+-- feed_name is the user's own name for the feed when they gave it one
+SELECT feed_follows.*, COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name, feeds.name AS original_name, feeds.url AS feed_url, users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM feed_posts
+        WHERE feed_posts.feed_id = feed_follows.feed_id
+        AND NOT EXISTS (
+            SELECT 1 FROM post_reads
+            WHERE post_reads.post_id = feed_posts.post_id
+            AND post_reads.user_id = feed_follows.user_id
+        )
+    ) AS unread_count
 FROM feed_follows
 INNER JOIN feeds ON feed_follows.feed_id = feeds.id
 INNER JOIN users ON feed_follows.user_id = users.id
@@ -29,4 +39,24 @@ AND feed_follows.feed_id = (
 );
 
 -- name: DeleteFeedFollows :exec
-DELETE FROM feed_follows;
\ No newline at end of file
+DELETE FROM feed_follows;
+
+-- name: GetFeedFollowByURL :one
+SELECT feed_follows.* FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1 AND feeds.url = $2
+LIMIT 1;
+
+-- name: SetFeedFollowName :one
+UPDATE feed_follows
+SET display_name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedFollowNotes :one
+UPDATE feed_follows
+SET notes = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
@@ -30,4 +30,71 @@ SELECT * FROM feeds
 WHERE url = $1 LIMIT 1;
 
 -- name: DeleteFeeds :exec
-DELETE FROM feeds;
\ No newline at end of file
+DELETE FROM feeds;
+
+-- name: GetFeedsForUser :many
+SELECT feeds.* FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.name;
+
+-- name: SetFeedRetention :one
+UPDATE feeds
+SET retention_keep_last = $2,
+    retention_max_age_seconds = $3,
+    retention_keep_unread = $4,
+    updated_at = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedURL :one
+UPDATE feeds
+SET url = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedOwner :one
+UPDATE feeds
+SET user_id = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: HandOffOwnedFeeds :execrows
+-- every feed of the user goes to its longest standing other follower,
+-- feeds nobody else follows are left without an owner
+UPDATE feeds
+SET user_id = (
+        SELECT feed_follows.user_id FROM feed_follows
+        WHERE feed_follows.feed_id = feeds.id
+        AND feed_follows.user_id <> sqlc.arg(user_id)
+        ORDER BY feed_follows.created_at
+        LIMIT 1
+    ),
+    updated_at = sqlc.arg(updated_at)
+WHERE feeds.user_id = sqlc.arg(user_id);
+
+-- name: GetOwnedFeedIDs :many
+SELECT id FROM feeds
+WHERE user_id = $1;
+
+-- name: DeleteOrphanedFeeds :execrows
+-- only the given feeds are looked at, those without an owner and followers are deleted
+DELETE FROM feeds
+WHERE id = ANY(sqlc.arg(ids)::uuid[])
+AND user_id IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = feeds.id
+);
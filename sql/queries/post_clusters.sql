@@ -0,0 +1,37 @@
+-- name: AddPostSighting :exec
+INSERT INTO post_sightings (post_id, feed_id, seen_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, feed_id) DO NOTHING;
+
+-- name: GetClusterCandidates :many
+-- fingerprinted posts of other feeds published around the same time, oldest first
+SELECT id, cluster_id, simhash FROM posts
+WHERE feed_id <> sqlc.arg(feed_id)
+AND simhash <> 0
+AND published_at >= sqlc.arg(since)
+AND published_at < sqlc.arg(until)
+ORDER BY created_at, id;
+
+-- name: SetPostCluster :exec
+UPDATE posts
+SET cluster_id = $2
+WHERE id = $1;
+
+-- name: GetClusterFeedNames :many
+-- the other feeds a post's story showed up in, named the way the user sees them
+SELECT DISTINCT COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name
+FROM feeds
+LEFT JOIN feed_follows ON feed_follows.feed_id = feeds.id AND feed_follows.user_id = sqlc.arg(user_id)
+WHERE feeds.id <> sqlc.arg(feed_id)
+AND (
+    feeds.id IN (
+        SELECT posts.feed_id FROM posts
+        WHERE posts.id = sqlc.arg(post_id) OR posts.cluster_id = sqlc.narg(cluster_id)::uuid
+    )
+    OR feeds.id IN (
+        SELECT post_sightings.feed_id FROM post_sightings
+        JOIN posts ON posts.id = post_sightings.post_id
+        WHERE posts.id = sqlc.arg(post_id) OR posts.cluster_id = sqlc.narg(cluster_id)::uuid
+    )
+)
+ORDER BY feed_name;
@@ -0,0 +1,24 @@
+-- name: MarkPostRead :exec
+INSERT INTO post_reads (user_id, post_id, read_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: MarkPostUnread :exec
+DELETE FROM post_reads
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: MarkAllPostsRead :execrows
+-- without a feed every followed feed is marked, posts sighted in a feed count as its own
+INSERT INTO post_reads (user_id, post_id, read_at)
+SELECT feed_follows.user_id, feed_posts.post_id, sqlc.arg(read_at)
+FROM feed_posts
+INNER JOIN feed_follows ON feed_posts.feed_id = feed_follows.feed_id
+WHERE feed_follows.user_id = sqlc.arg(user_id)
+AND (sqlc.narg(feed_id)::uuid IS NULL OR feed_posts.feed_id = sqlc.narg(feed_id)::uuid)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: GetReadPostsForUser :many
+SELECT posts.*, post_reads.read_at FROM posts
+INNER JOIN post_reads ON post_reads.post_id = posts.id
+WHERE post_reads.user_id = $1
+ORDER BY post_reads.read_at DESC;
@@ -0,0 +1,18 @@
+-- name: CreatePostRevision :one
+INSERT INTO post_revisions (id, created_at, post_id, title, description, content, content_hash)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetLatestPostRevision :one
+SELECT * FROM post_revisions
+WHERE post_id = $1
+ORDER BY created_at DESC
+LIMIT 1;
@@ -1,5 +1,5 @@
 -- name: CreatePost :one
-INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id)
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, simhash, author, categories)
 VALUES (
     $1,
     $2,
@@ -8,13 +8,147 @@ VALUES (
     $5,
     $6,
     $7,
-    $8
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
 )
 RETURNING *;
 
 -- name: GetPostsForUser :many
+-- keyset paginated by publish or fetch time, newest first unless reverse, the rows after the cursor when one is given.
+-- The filters are only written in browsable, each branch below is one sort and direction and only
+-- the one picked by sort_by_fetched and reverse returns rows.
+-- Posts sighted in a followed feed are shown through it too, once per post:
+-- through the post's own feed when it is followed, else through the followed feed with the lowest id that sighted it.
+-- A story clustered over several feeds is shown once, by its earliest fetched post among the browsed ones.
+WITH browsable AS NOT MATERIALIZED (
+    SELECT posts.*, (post_reads.post_id IS NOT NULL)::bool AS is_read,
+        COALESCE(feed_follows.display_name, feeds.name)::text AS feed_name, feeds.id AS browsed_feed_id
+    FROM feed_posts
+    JOIN posts ON posts.id = feed_posts.post_id
+    JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+    JOIN feeds ON feeds.id = feed_posts.feed_id
+    LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = feed_follows.user_id
+    WHERE feed_follows.user_id = sqlc.arg(user_id)
+    AND (NOT sqlc.arg(unread_only)::bool OR post_reads.post_id IS NULL)
+    AND (sqlc.narg(folder_id)::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_follow_folders
+        WHERE feed_follow_folders.feed_follow_id = feed_follows.id
+        AND feed_follow_folders.folder_id = sqlc.narg(folder_id)::uuid
+    ))
+    AND (sqlc.narg(feed_id)::uuid IS NULL OR feed_posts.feed_id = sqlc.narg(feed_id)::uuid)
+    AND (sqlc.narg(since)::timestamptz IS NULL OR posts.published_at >= sqlc.narg(since)::timestamptz)
+    AND (sqlc.narg(until)::timestamptz IS NULL OR posts.published_at < sqlc.narg(until)::timestamptz)
+    AND (sqlc.narg(title)::text IS NULL OR posts.title ILIKE '%' || sqlc.narg(title)::text || '%')
+),
+shown AS NOT MATERIALIZED (
+    SELECT * FROM browsable
+    WHERE NOT EXISTS (
+        SELECT 1 FROM browsable AS other
+        WHERE other.id = browsable.id
+        AND (other.browsed_feed_id <> other.feed_id, other.browsed_feed_id) < (browsable.browsed_feed_id <> browsable.feed_id, browsable.browsed_feed_id)
+    )
+    AND (browsable.cluster_id IS NULL OR NOT EXISTS (
+        SELECT 1 FROM browsable AS earlier
+        WHERE earlier.cluster_id = browsable.cluster_id
+        AND (earlier.created_at, earlier.id) < (browsable.created_at, browsable.id)
+    ))
+)
+(
+    SELECT * FROM shown
+    WHERE NOT sqlc.arg(sort_by_fetched)::bool AND NOT sqlc.arg(reverse)::bool
+    AND (sqlc.narg(cursor_time)::timestamptz IS NULL OR (published_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.arg(cursor_id)::uuid))
+    ORDER BY published_at DESC, id DESC
+    LIMIT sqlc.arg('limit')
+)
+UNION ALL
+(
+    SELECT * FROM shown
+    WHERE NOT sqlc.arg(sort_by_fetched)::bool AND sqlc.arg(reverse)::bool
+    AND (sqlc.narg(cursor_time)::timestamptz IS NULL OR (published_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.arg(cursor_id)::uuid))
+    ORDER BY published_at ASC, id ASC
+    LIMIT sqlc.arg('limit')
+)
+UNION ALL
+(
+    SELECT * FROM shown
+    WHERE sqlc.arg(sort_by_fetched)::bool AND NOT sqlc.arg(reverse)::bool
+    AND (sqlc.narg(cursor_time)::timestamptz IS NULL OR (created_at, id) < (sqlc.narg(cursor_time)::timestamptz, sqlc.arg(cursor_id)::uuid))
+    ORDER BY created_at DESC, id DESC
+    LIMIT sqlc.arg('limit')
+)
+UNION ALL
+(
+    SELECT * FROM shown
+    WHERE sqlc.arg(sort_by_fetched)::bool AND sqlc.arg(reverse)::bool
+    AND (sqlc.narg(cursor_time)::timestamptz IS NULL OR (created_at, id) > (sqlc.narg(cursor_time)::timestamptz, sqlc.arg(cursor_id)::uuid))
+    ORDER BY created_at ASC, id ASC
+    LIMIT sqlc.arg('limit')
+);
+
+-- name: GetPostByFeedGUID :one
+SELECT * FROM posts
+WHERE feed_id = $1 AND guid = $2 LIMIT 1;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts
+WHERE url = $1 LIMIT 1;
+
+-- name: UpdatePostContent :one
+UPDATE posts
+SET title = $2,
+    description = $3,
+    content = $4,
+    content_hash = $5,
+    guid = $6,
+    updated_at = $7,
+    url = $8,
+    revised_at = COALESCE(sqlc.narg(revised_at), revised_at)
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPrunablePosts :many
+-- keep_last and older_than are independent limits, a post breaking any of them can be pruned
+-- unless keep_unread protects posts some follower of the feed hasn't read yet
+-- starred posts and posts in a read later queue are never pruned
 SELECT posts.* FROM posts
-JOIN feed_follows ON posts.feed_id = feed_follows.feed_id
-WHERE feed_follows.user_id = $1
-ORDER BY posts.published_at DESC
-LIMIT $2;
+WHERE posts.feed_id = sqlc.arg(feed_id)
+AND (
+    (sqlc.narg(keep_last)::int IS NOT NULL AND posts.id NOT IN (
+        SELECT newest.id FROM posts AS newest
+        WHERE newest.feed_id = sqlc.arg(feed_id)
+        ORDER BY newest.published_at DESC, newest.id DESC
+        LIMIT sqlc.narg(keep_last)::int
+    ))
+    OR (sqlc.narg(older_than)::timestamptz IS NOT NULL AND posts.published_at < sqlc.narg(older_than)::timestamptz)
+)
+AND (NOT sqlc.arg(keep_unread)::bool OR NOT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.feed_id = posts.feed_id
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id
+        AND post_reads.user_id = feed_follows.user_id
+    )
+))
+AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+AND NOT EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id)
+ORDER BY posts.published_at;
+
+-- name: DeletePostsByIDs :exec
+DELETE FROM posts
+WHERE id = ANY(sqlc.arg(ids)::uuid[]);
+
+-- name: GetPostByID :one
+SELECT * FROM posts
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLatestPostForFeed :one
+SELECT * FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC, id DESC
+LIMIT 1;
@@ -0,0 +1,34 @@
+-- name: SearchPosts :many
+-- without user_id every post is searched, headlines are only built for the returned page
+-- posts sighted in a followed feed are searched as well, like browse shows them
+WITH matches AS (
+    SELECT posts.id, posts.title, posts.url, posts.description, posts.content, posts.published_at, posts.feed_id,
+        ts_rank(posts.search_vector, to_tsquery('english', sqlc.arg(query))) AS rank
+    FROM searchable_posts AS posts
+    WHERE posts.search_vector @@ to_tsquery('english', sqlc.arg(query))
+    AND (sqlc.narg(user_id)::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_posts
+        INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+        WHERE feed_posts.post_id = posts.id
+        AND feed_follows.user_id = sqlc.narg(user_id)::uuid
+    ))
+    AND (sqlc.narg(folder_id)::uuid IS NULL OR EXISTS (
+        SELECT 1 FROM feed_posts
+        INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+        INNER JOIN feed_follow_folders ON feed_follow_folders.feed_follow_id = feed_follows.id
+        WHERE feed_posts.post_id = posts.id
+        AND feed_follow_folders.folder_id = sqlc.narg(folder_id)::uuid
+    ))
+    AND (sqlc.narg(since)::timestamptz IS NULL OR posts.published_at >= sqlc.narg(since)::timestamptz)
+    AND (sqlc.narg(until)::timestamptz IS NULL OR posts.published_at < sqlc.narg(until)::timestamptz)
+    ORDER BY rank DESC, posts.published_at DESC
+    LIMIT sqlc.arg('limit')
+)
+SELECT matches.id, matches.url, matches.published_at, matches.rank, feeds.name AS feed_name,
+    ts_headline('english', matches.title, to_tsquery('english', sqlc.arg(query)),
+        'HighlightAll=true, StartSel=**, StopSel=**')::text AS title_headline,
+    ts_headline('english', COALESCE(matches.content, matches.description, ''), to_tsquery('english', sqlc.arg(query)),
+        'MaxFragments=2, MaxWords=25, MinWords=10, StartSel=**, StopSel=**')::text AS snippet
+FROM matches
+INNER JOIN feeds ON feeds.id = matches.feed_id
+ORDER BY matches.rank DESC, matches.published_at DESC;
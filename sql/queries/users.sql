@@ -1,10 +1,11 @@
 -- name: CreateUser :one
-INSERT INTO users (id, created_at, updated_at, name)
+INSERT INTO users (id, created_at, updated_at, name, is_admin)
 VALUES (
     $1,
     $2,
     $3,
-    $4
+    $4,
+    $5
 )
 RETURNING *;
 
@@ -20,4 +21,29 @@ WHERE id = $1 LIMIT 1;
 DELETE FROM users;
 
 -- name: GetAllUsers :many
-SELECT * FROM users;
\ No newline at end of file
+SELECT * FROM users;
+
+-- name: SetUserAdmin :one
+UPDATE users
+SET is_admin = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetUserTimezone :one
+UPDATE users
+SET timezone = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: RenameUser :one
+UPDATE users
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
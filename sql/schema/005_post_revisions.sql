@@ -0,0 +1,31 @@
+-- +goose Up
+ALTER TABLE posts
+    ADD COLUMN guid TEXT,
+    ADD COLUMN content TEXT,
+    ADD COLUMN content_hash VARCHAR(64) NOT NULL DEFAULT '',
+    ADD COLUMN revised_at TIMESTAMP;
+
+CREATE UNIQUE INDEX posts_feed_id_guid_idx ON posts (feed_id, guid);
+
+CREATE TABLE post_revisions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    content TEXT,
+    content_hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX post_revisions_post_id_idx ON post_revisions (post_id, created_at);
+
+-- +goose Down
+DROP TABLE post_revisions;
+
+DROP INDEX posts_feed_id_guid_idx;
+
+ALTER TABLE posts
+    DROP COLUMN guid,
+    DROP COLUMN content,
+    DROP COLUMN content_hash,
+    DROP COLUMN revised_at;
@@ -0,0 +1,13 @@
+-- +goose Up
+ALTER TABLE feeds
+    ADD COLUMN retention_keep_last INTEGER,
+    ADD COLUMN retention_max_age_seconds BIGINT;
+
+CREATE INDEX posts_feed_id_published_at_idx ON posts (feed_id, published_at);
+
+-- +goose Down
+DROP INDEX posts_feed_id_published_at_idx;
+
+ALTER TABLE feeds
+    DROP COLUMN retention_keep_last,
+    DROP COLUMN retention_max_age_seconds;
@@ -0,0 +1,28 @@
+-- +goose Up
+CREATE TABLE hooks (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    template VARCHAR(20) NOT NULL,
+    max_attempts INTEGER NOT NULL
+);
+
+CREATE TABLE hook_deliveries (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    hook_id UUID NOT NULL REFERENCES hooks(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    error TEXT
+);
+
+CREATE INDEX hook_deliveries_hook_id_idx ON hook_deliveries (hook_id, created_at);
+
+-- +goose Down
+DROP TABLE hook_deliveries;
+DROP TABLE hooks;
@@ -0,0 +1,16 @@
+-- +goose Up
+CREATE TABLE post_reads (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX post_reads_post_id_idx ON post_reads (post_id);
+
+ALTER TABLE feeds ADD COLUMN retention_keep_unread BOOLEAN;
+
+-- +goose Down
+ALTER TABLE feeds DROP COLUMN retention_keep_unread;
+
+DROP TABLE post_reads;
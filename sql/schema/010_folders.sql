@@ -0,0 +1,21 @@
+-- +goose Up
+CREATE TABLE folders (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE feed_follow_folders (
+    feed_follow_id UUID NOT NULL REFERENCES feed_follows(id) ON DELETE CASCADE,
+    folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    PRIMARY KEY (feed_follow_id, folder_id)
+);
+
+CREATE INDEX feed_follow_folders_folder_id_idx ON feed_follow_folders (folder_id);
+
+-- +goose Down
+DROP TABLE feed_follow_folders;
+DROP TABLE folders;
@@ -0,0 +1,9 @@
+-- +goose Up
+ALTER TABLE feed_follows
+    ADD COLUMN display_name VARCHAR(100),
+    ADD COLUMN notes TEXT;
+
+-- +goose Down
+ALTER TABLE feed_follows
+    DROP COLUMN display_name,
+    DROP COLUMN notes;
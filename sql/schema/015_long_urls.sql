@@ -0,0 +1,39 @@
+-- +goose Up
+-- the search vector is generated from the title, so it has to go while the title changes
+DROP INDEX posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN search_vector;
+
+ALTER TABLE feeds ALTER COLUMN url TYPE TEXT;
+
+ALTER TABLE posts ALTER COLUMN url TYPE TEXT;
+ALTER TABLE posts ALTER COLUMN title TYPE TEXT;
+
+ALTER TABLE post_revisions ALTER COLUMN title TYPE TEXT;
+
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR NOT NULL GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'C')
+) STORED;
+
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (search_vector);
+
+-- +goose Down
+-- longer urls and titles don't fit anymore, they are cut
+DROP INDEX posts_search_vector_idx;
+ALTER TABLE posts DROP COLUMN search_vector;
+
+ALTER TABLE post_revisions ALTER COLUMN title TYPE VARCHAR(200) USING LEFT(title, 200);
+
+ALTER TABLE posts ALTER COLUMN title TYPE VARCHAR(200) USING LEFT(title, 200);
+ALTER TABLE posts ALTER COLUMN url TYPE VARCHAR(150) USING LEFT(url, 150);
+
+ALTER TABLE feeds ALTER COLUMN url TYPE VARCHAR(150) USING LEFT(url, 150);
+
+ALTER TABLE posts ADD COLUMN search_vector TSVECTOR NOT NULL GENERATED ALWAYS AS (
+    setweight(to_tsvector('english', COALESCE(title, '')), 'A') ||
+    setweight(to_tsvector('english', COALESCE(description, '')), 'B') ||
+    setweight(to_tsvector('english', COALESCE(content, '')), 'C')
+) STORED;
+
+CREATE INDEX posts_search_vector_idx ON posts USING GIN (search_vector);
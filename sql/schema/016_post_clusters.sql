@@ -0,0 +1,27 @@
+-- +goose Up
+-- posts telling the same story in different feeds share a cluster_id,
+-- simhash is the fingerprint of the title and description they are matched by (0 when there is too little text)
+ALTER TABLE posts ADD COLUMN simhash BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE posts ADD COLUMN cluster_id UUID;
+
+CREATE INDEX posts_cluster_id_idx ON posts (cluster_id);
+CREATE INDEX posts_published_at_idx ON posts (published_at);
+
+-- a link is stored once, other feeds carrying the very same link are remembered here
+CREATE TABLE post_sightings (
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    seen_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (post_id, feed_id)
+);
+
+CREATE INDEX post_sightings_feed_id_idx ON post_sightings (feed_id);
+
+-- +goose Down
+DROP TABLE post_sightings;
+
+DROP INDEX posts_published_at_idx;
+DROP INDEX posts_cluster_id_idx;
+
+ALTER TABLE posts DROP COLUMN cluster_id;
+ALTER TABLE posts DROP COLUMN simhash;
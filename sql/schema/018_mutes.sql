@@ -0,0 +1,22 @@
+-- +goose Up
+-- categories are kept one per line
+ALTER TABLE posts ADD COLUMN author TEXT NOT NULL DEFAULT '';
+ALTER TABLE posts ADD COLUMN categories TEXT NOT NULL DEFAULT '';
+
+-- rules hiding posts from one user, for every followed feed when feed_id is NULL
+CREATE TABLE mutes (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    pattern TEXT NOT NULL
+);
+
+CREATE INDEX mutes_user_id_idx ON mutes (user_id);
+
+-- +goose Down
+DROP TABLE mutes;
+
+ALTER TABLE posts DROP COLUMN categories;
+ALTER TABLE posts DROP COLUMN author;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- search_vector is only read by search, everything else reads posts through a view which leaves it out,
+-- a column added to the table later has to be added to the view as well
+ALTER TABLE posts RENAME TO searchable_posts;
+
+CREATE VIEW posts AS
+SELECT id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, revised_at, simhash, cluster_id, author, categories
+FROM searchable_posts;
+
+-- +goose Down
+DROP VIEW posts;
+ALTER TABLE searchable_posts RENAME TO posts;
@@ -0,0 +1,9 @@
+-- +goose Up
+-- the posts each feed carries, its own ones and the ones sighted in it
+CREATE VIEW feed_posts AS
+SELECT posts.id AS post_id, posts.feed_id FROM posts
+UNION ALL
+SELECT post_sightings.post_id, post_sightings.feed_id FROM post_sightings;
+
+-- +goose Down
+DROP VIEW feed_posts;
@@ -0,0 +1,7 @@
+// Package schema holds the goose migrations of the database, they are built into the binary.
+package schema
+
+import "embed"
+
+//go:embed *.sql
+var FS embed.FS
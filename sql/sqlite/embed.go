@@ -0,0 +1,10 @@
+// Package sqlite holds the migrations and queries of the SQLite backend, they are built into the binary.
+package sqlite
+
+import "embed"
+
+//go:embed schema/*.sql
+var Schema embed.FS
+
+//go:embed queries/*.sql
+var Queries embed.FS
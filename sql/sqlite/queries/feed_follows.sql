@@ -0,0 +1,53 @@
+-- name: CreateFeedFollow :one
+-- SQLite can't insert inside a WITH, the names come from subqueries in RETURNING instead
+INSERT INTO feed_follows (id, created_at, updated_at, user_id, feed_id)
+VALUES ($1, $2, $3, $4, $5)
+RETURNING *,
+    (SELECT feeds.name FROM feeds WHERE feeds.id = feed_id) AS feed_name,
+    (SELECT users.name FROM users WHERE users.id = user_id) AS user_name;
+
+-- name: GetFeedFollowsForUser :many
+SELECT feed_follows.*, COALESCE(feed_follows.display_name, feeds.name) AS feed_name, feeds.name AS original_name, feeds.url AS feed_url, users.name AS user_name,
+    (
+        SELECT COUNT(*) FROM feed_posts
+        WHERE feed_posts.feed_id = feed_follows.feed_id
+        AND NOT EXISTS (
+            SELECT 1 FROM post_reads
+            WHERE post_reads.post_id = feed_posts.post_id
+            AND post_reads.user_id = feed_follows.user_id
+        )
+    ) AS unread_count
+FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+INNER JOIN users ON feed_follows.user_id = users.id
+WHERE feed_follows.user_id = $1;
+
+-- name: DeleteFeedFollowByUrl :exec
+DELETE FROM feed_follows
+WHERE feed_follows.user_id = $1
+AND feed_follows.feed_id = (
+    SELECT feeds.id FROM feeds WHERE feeds.url = $2
+);
+
+-- name: DeleteFeedFollows :exec
+DELETE FROM feed_follows;
+
+-- name: GetFeedFollowByURL :one
+SELECT feed_follows.* FROM feed_follows
+INNER JOIN feeds ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1 AND feeds.url = $2
+LIMIT 1;
+
+-- name: SetFeedFollowName :one
+UPDATE feed_follows
+SET display_name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedFollowNotes :one
+UPDATE feed_follows
+SET notes = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
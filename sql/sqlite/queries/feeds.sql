@@ -0,0 +1,100 @@
+-- name: CreateFeed :one
+INSERT INTO feeds (id, created_at, updated_at, name, url, user_id)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: MarkFeedFetched :one
+UPDATE feeds
+SET last_fetched_at = $1,
+    updated_at = $2
+WHERE id = $3
+RETURNING *;
+
+-- name: GetNextFeedToFetch :one
+SELECT * FROM feeds
+ORDER BY last_fetched_at ASC NULLS FIRST
+LIMIT 1;
+
+-- name: GetAllFeeds :many
+SELECT * FROM feeds;
+
+-- name: GetFeedByURL :one
+SELECT * FROM feeds
+WHERE url = $1 LIMIT 1;
+
+-- name: DeleteFeeds :exec
+DELETE FROM feeds;
+
+-- name: GetFeedsForUser :many
+SELECT feeds.* FROM feeds
+INNER JOIN feed_follows ON feed_follows.feed_id = feeds.id
+WHERE feed_follows.user_id = $1
+ORDER BY feeds.name;
+
+-- name: SetFeedRetention :one
+UPDATE feeds
+SET retention_keep_last = $2,
+    retention_max_age_seconds = $3,
+    retention_keep_unread = $4,
+    updated_at = $5
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFeed :exec
+DELETE FROM feeds
+WHERE id = $1;
+
+-- name: RenameFeed :one
+UPDATE feeds
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedURL :one
+UPDATE feeds
+SET url = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetFeedOwner :one
+UPDATE feeds
+SET user_id = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: HandOffOwnedFeeds :execrows
+-- every feed of the user goes to its longest standing other follower,
+-- feeds nobody else follows are left without an owner
+UPDATE feeds
+SET user_id = (
+        SELECT feed_follows.user_id FROM feed_follows
+        WHERE feed_follows.feed_id = feeds.id
+        AND feed_follows.user_id <> $1
+        ORDER BY feed_follows.created_at
+        LIMIT 1
+    ),
+    updated_at = $2
+WHERE feeds.user_id = $1;
+
+-- name: GetOwnedFeedIDs :many
+SELECT id FROM feeds
+WHERE user_id = $1;
+
+-- name: DeleteOrphanedFeeds :execrows
+-- the ids arrive as a Postgres array literal, {id,id,...}
+DELETE FROM feeds
+WHERE instr($1, id) > 0
+AND user_id IS NULL
+AND NOT EXISTS (
+    SELECT 1 FROM feed_follows WHERE feed_follows.feed_id = feeds.id
+);
@@ -0,0 +1,50 @@
+-- name: CreateFolder :one
+INSERT INTO folders (id, created_at, updated_at, user_id, name)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetFolderByName :one
+SELECT * FROM folders
+WHERE user_id = $1 AND name = $2 LIMIT 1;
+
+-- name: GetFoldersForUser :many
+SELECT * FROM folders
+WHERE user_id = $1
+ORDER BY name;
+
+-- name: RenameFolder :one
+UPDATE folders
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteFolder :exec
+DELETE FROM folders
+WHERE id = $1;
+
+-- name: AddFollowToFolder :exec
+INSERT INTO feed_follow_folders (feed_follow_id, folder_id)
+VALUES ($1, $2)
+ON CONFLICT (feed_follow_id, folder_id) DO NOTHING;
+
+-- name: RemoveFollowFromFolder :execrows
+DELETE FROM feed_follow_folders
+WHERE feed_follow_id = $1 AND folder_id = $2;
+
+-- name: RemoveFollowFromAllFolders :exec
+DELETE FROM feed_follow_folders
+WHERE feed_follow_id = $1;
+
+-- name: GetFollowFoldersForUser :many
+SELECT feed_follow_folders.feed_follow_id, folders.id AS folder_id, folders.name AS folder_name
+FROM feed_follow_folders
+INNER JOIN folders ON feed_follow_folders.folder_id = folders.id
+WHERE folders.user_id = $1
+ORDER BY folders.name;
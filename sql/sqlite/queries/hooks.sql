@@ -0,0 +1,57 @@
+-- name: CreateHook :one
+INSERT INTO hooks (id, created_at, updated_at, user_id, feed_id, kind, target, template, max_attempts)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9
+)
+RETURNING *;
+
+-- name: GetHooksForUser :many
+SELECT hooks.*, feeds.name AS feed_name
+FROM hooks
+LEFT JOIN feeds ON hooks.feed_id = feeds.id
+WHERE hooks.user_id = $1
+ORDER BY hooks.created_at;
+
+-- name: GetHooksForFeed :many
+-- hooks without a feed fire for every feed their user follows
+SELECT hooks.* FROM hooks
+WHERE hooks.feed_id = $1
+OR (hooks.feed_id IS NULL AND EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.user_id = hooks.user_id
+    AND feed_follows.feed_id = $1
+));
+
+-- name: DeleteHook :execrows
+DELETE FROM hooks
+WHERE id = $1 AND user_id = $2;
+
+-- name: CreateHookDelivery :one
+INSERT INTO hook_deliveries (id, created_at, hook_id, post_id, attempts, succeeded, error)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7
+)
+RETURNING *;
+
+-- name: GetHookDeliveriesForUser :many
+SELECT hook_deliveries.*, hooks.kind AS hook_kind, hooks.target AS hook_target, posts.title AS post_title
+FROM hook_deliveries
+INNER JOIN hooks ON hook_deliveries.hook_id = hooks.id
+INNER JOIN posts ON hook_deliveries.post_id = posts.id
+WHERE hooks.user_id = $1
+ORDER BY hook_deliveries.created_at DESC
+LIMIT $2;
@@ -0,0 +1,22 @@
+-- name: CreateMute :one
+INSERT INTO mutes (id, created_at, user_id, feed_id, kind, pattern)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6
+)
+RETURNING *;
+
+-- name: GetMutesForUser :many
+SELECT mutes.*, feeds.name AS feed_name
+FROM mutes
+LEFT JOIN feeds ON mutes.feed_id = feeds.id
+WHERE mutes.user_id = $1
+ORDER BY mutes.created_at, mutes.id;
+
+-- name: DeleteMute :execrows
+DELETE FROM mutes
+WHERE id = $1 AND user_id = $2;
@@ -0,0 +1,37 @@
+-- name: AddPostSighting :exec
+INSERT INTO post_sightings (post_id, feed_id, seen_at)
+VALUES ($1, $2, $3)
+ON CONFLICT (post_id, feed_id) DO NOTHING;
+
+-- name: GetClusterCandidates :many
+-- $1 feed_id, $2 since, $3 until
+SELECT id, cluster_id, simhash FROM posts
+WHERE feed_id <> $1
+AND simhash <> 0
+AND published_at >= $2
+AND published_at < $3
+ORDER BY created_at, id;
+
+-- name: SetPostCluster :exec
+UPDATE posts
+SET cluster_id = $2
+WHERE id = $1;
+
+-- name: GetClusterFeedNames :many
+-- $1 user_id, $2 feed_id, $3 post_id, $4 cluster_id
+SELECT DISTINCT COALESCE(feed_follows.display_name, feeds.name) AS feed_name
+FROM feeds
+LEFT JOIN feed_follows ON feed_follows.feed_id = feeds.id AND feed_follows.user_id = $1
+WHERE feeds.id <> $2
+AND (
+    feeds.id IN (
+        SELECT posts.feed_id FROM posts
+        WHERE posts.id = $3 OR posts.cluster_id = $4
+    )
+    OR feeds.id IN (
+        SELECT post_sightings.feed_id FROM post_sightings
+        JOIN posts ON posts.id = post_sightings.post_id
+        WHERE posts.id = $3 OR posts.cluster_id = $4
+    )
+)
+ORDER BY feed_name;
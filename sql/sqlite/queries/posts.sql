@@ -0,0 +1,150 @@
+-- name: CreatePost :one
+INSERT INTO posts (id, created_at, updated_at, title, url, description, published_at, feed_id, guid, content, content_hash, simhash, author, categories)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5,
+    $6,
+    $7,
+    $8,
+    $9,
+    $10,
+    $11,
+    $12,
+    $13,
+    $14
+)
+RETURNING *;
+
+-- name: GetPostsForUser :many
+-- $1 user_id, $2 unread_only, $3 folder_id, $4 feed_id, $5 since, $6 until, $7 title,
+-- $8 sort_by_fetched, $9 reverse, $10 cursor_time, $11 cursor_id, $12 limit
+-- SQLite only orders and limits a compound select as a whole, so each branch is a subquery
+WITH browsable AS NOT MATERIALIZED (
+    SELECT posts.*, (post_reads.post_id IS NOT NULL) AS is_read,
+        COALESCE(feed_follows.display_name, feeds.name) AS feed_name, feeds.id AS browsed_feed_id
+    FROM feed_posts
+    JOIN posts ON posts.id = feed_posts.post_id
+    JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+    JOIN feeds ON feeds.id = feed_posts.feed_id
+    LEFT JOIN post_reads ON post_reads.post_id = posts.id AND post_reads.user_id = feed_follows.user_id
+    WHERE feed_follows.user_id = $1
+    AND (NOT $2 OR post_reads.post_id IS NULL)
+    AND ($3 IS NULL OR EXISTS (
+        SELECT 1 FROM feed_follow_folders
+        WHERE feed_follow_folders.feed_follow_id = feed_follows.id
+        AND feed_follow_folders.folder_id = $3
+    ))
+    AND ($4 IS NULL OR feed_posts.feed_id = $4)
+    AND ($5 IS NULL OR posts.published_at >= $5)
+    AND ($6 IS NULL OR posts.published_at < $6)
+    AND ($7 IS NULL OR posts.title LIKE '%' || $7 || '%' ESCAPE '\')
+),
+shown AS NOT MATERIALIZED (
+    SELECT * FROM browsable
+    WHERE NOT EXISTS (
+        SELECT 1 FROM browsable AS other
+        WHERE other.id = browsable.id
+        AND (other.browsed_feed_id <> other.feed_id, other.browsed_feed_id) < (browsable.browsed_feed_id <> browsable.feed_id, browsable.browsed_feed_id)
+    )
+    AND (browsable.cluster_id IS NULL OR NOT EXISTS (
+        SELECT 1 FROM browsable AS earlier
+        WHERE earlier.cluster_id = browsable.cluster_id
+        AND (earlier.created_at, earlier.id) < (browsable.created_at, browsable.id)
+    ))
+)
+SELECT * FROM (
+    SELECT * FROM shown
+    WHERE NOT $8 AND NOT $9
+    AND ($10 IS NULL OR (published_at, id) < ($10, $11))
+    ORDER BY published_at DESC, id DESC
+    LIMIT $12
+)
+UNION ALL
+SELECT * FROM (
+    SELECT * FROM shown
+    WHERE NOT $8 AND $9
+    AND ($10 IS NULL OR (published_at, id) > ($10, $11))
+    ORDER BY published_at ASC, id ASC
+    LIMIT $12
+)
+UNION ALL
+SELECT * FROM (
+    SELECT * FROM shown
+    WHERE $8 AND NOT $9
+    AND ($10 IS NULL OR (created_at, id) < ($10, $11))
+    ORDER BY created_at DESC, id DESC
+    LIMIT $12
+)
+UNION ALL
+SELECT * FROM (
+    SELECT * FROM shown
+    WHERE $8 AND $9
+    AND ($10 IS NULL OR (created_at, id) > ($10, $11))
+    ORDER BY created_at ASC, id ASC
+    LIMIT $12
+);
+
+-- name: GetPostByFeedGUID :one
+SELECT * FROM posts
+WHERE feed_id = $1 AND guid = $2 LIMIT 1;
+
+-- name: GetPostByURL :one
+SELECT * FROM posts
+WHERE url = $1 LIMIT 1;
+
+-- name: UpdatePostContent :one
+UPDATE posts
+SET title = $2,
+    description = $3,
+    content = $4,
+    content_hash = $5,
+    guid = $6,
+    updated_at = $7,
+    url = $8,
+    revised_at = COALESCE($9, revised_at)
+WHERE id = $1
+RETURNING *;
+
+-- name: GetPrunablePosts :many
+-- $1 feed_id, $2 keep_last, $3 older_than, $4 keep_unread
+SELECT posts.* FROM posts
+WHERE posts.feed_id = $1
+AND (
+    ($2 IS NOT NULL AND posts.id NOT IN (
+        SELECT newest.id FROM posts AS newest
+        WHERE newest.feed_id = $1
+        ORDER BY newest.published_at DESC, newest.id DESC
+        LIMIT COALESCE($2, 0)
+    ))
+    OR ($3 IS NOT NULL AND posts.published_at < $3)
+)
+AND (NOT $4 OR NOT EXISTS (
+    SELECT 1 FROM feed_follows
+    WHERE feed_follows.feed_id = posts.feed_id
+    AND NOT EXISTS (
+        SELECT 1 FROM post_reads
+        WHERE post_reads.post_id = posts.id
+        AND post_reads.user_id = feed_follows.user_id
+    )
+))
+AND NOT EXISTS (SELECT 1 FROM post_stars WHERE post_stars.post_id = posts.id)
+AND NOT EXISTS (SELECT 1 FROM read_later WHERE read_later.post_id = posts.id)
+ORDER BY posts.published_at;
+
+-- name: DeletePostsByIDs :exec
+-- the ids arrive as a Postgres array literal, {id,id,...}
+DELETE FROM posts
+WHERE instr($1, id) > 0;
+
+-- name: GetPostByID :one
+SELECT * FROM posts
+WHERE id = $1 LIMIT 1;
+
+-- name: GetLatestPostForFeed :one
+SELECT * FROM posts
+WHERE feed_id = $1
+ORDER BY published_at DESC, id DESC
+LIMIT 1;
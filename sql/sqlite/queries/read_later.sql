@@ -0,0 +1,19 @@
+-- name: AddToReadLater :exec
+INSERT INTO read_later (user_id, post_id, position, added_at)
+VALUES (
+    $1,
+    $2,
+    COALESCE((SELECT MAX(position) FROM read_later WHERE read_later.user_id = $1), 0) + 1,
+    $3
+)
+ON CONFLICT (user_id, post_id) DO NOTHING;
+
+-- name: RemoveFromReadLater :execrows
+DELETE FROM read_later
+WHERE user_id = $1 AND post_id = $2;
+
+-- name: GetReadLaterForUser :many
+SELECT posts.*, read_later.position, read_later.added_at FROM posts
+INNER JOIN read_later ON read_later.post_id = posts.id
+WHERE read_later.user_id = $1
+ORDER BY read_later.position;
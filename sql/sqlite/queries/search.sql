@@ -0,0 +1,29 @@
+-- name: SearchPosts :many
+-- $1 query, $2 user_id, $3 folder_id, $4 since, $5 until, $6 limit
+-- the tsquery_* functions are registered by internal/database/sqlite
+-- posts sighted in a followed feed are searched as well, like browse shows them
+SELECT posts.id, posts.url, posts.published_at,
+    tsquery_rank($1, posts.title, posts.description, posts.content) AS rank,
+    feeds.name AS feed_name,
+    tsquery_headline($1, posts.title, 0) AS title_headline,
+    tsquery_headline($1, COALESCE(posts.content, posts.description, ''), 25) AS snippet
+FROM posts
+INNER JOIN feeds ON feeds.id = posts.feed_id
+WHERE tsquery_match($1, posts.title || ' ' || COALESCE(posts.description, '') || ' ' || COALESCE(posts.content, ''))
+AND ($2 IS NULL OR EXISTS (
+    SELECT 1 FROM feed_posts
+    INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+    WHERE feed_posts.post_id = posts.id
+    AND feed_follows.user_id = $2
+))
+AND ($3 IS NULL OR EXISTS (
+    SELECT 1 FROM feed_posts
+    INNER JOIN feed_follows ON feed_follows.feed_id = feed_posts.feed_id
+    INNER JOIN feed_follow_folders ON feed_follow_folders.feed_follow_id = feed_follows.id
+    WHERE feed_posts.post_id = posts.id
+    AND feed_follow_folders.folder_id = $3
+))
+AND ($4 IS NULL OR posts.published_at >= $4)
+AND ($5 IS NULL OR posts.published_at < $5)
+ORDER BY rank DESC, posts.published_at DESC
+LIMIT $6;
@@ -0,0 +1,49 @@
+-- name: CreateUser :one
+INSERT INTO users (id, created_at, updated_at, name, is_admin)
+VALUES (
+    $1,
+    $2,
+    $3,
+    $4,
+    $5
+)
+RETURNING *;
+
+-- name: GetUserByName :one
+SELECT * FROM users
+WHERE name = $1 LIMIT 1;
+
+-- name: GetUserByID :one
+SELECT * FROM users
+WHERE id = $1 LIMIT 1;
+
+-- name: DeleteUsers :exec
+DELETE FROM users;
+
+-- name: GetAllUsers :many
+SELECT * FROM users;
+
+-- name: SetUserAdmin :one
+UPDATE users
+SET is_admin = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: SetUserTimezone :one
+UPDATE users
+SET timezone = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: RenameUser :one
+UPDATE users
+SET name = $2,
+    updated_at = $3
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteUser :exec
+DELETE FROM users
+WHERE id = $1;
@@ -0,0 +1,151 @@
+-- +goose Up
+-- columns are in the same order as in the Postgres schema,
+-- the generated code scans `SELECT *` rows positionally
+CREATE TABLE users (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    name VARCHAR(50) UNIQUE NOT NULL,
+    is_admin BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE TABLE feeds (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    last_fetched_at TIMESTAMP,
+    name VARCHAR(100) NOT NULL,
+    url VARCHAR(150) UNIQUE NOT NULL,
+    user_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    retention_keep_last INTEGER,
+    retention_max_age_seconds BIGINT,
+    retention_keep_unread BOOLEAN
+);
+
+CREATE TABLE feed_follows (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    display_name VARCHAR(100),
+    notes TEXT,
+    UNIQUE (user_id, feed_id)
+);
+
+-- search_vector only keeps the column layout of Postgres, SQLite searches the text itself
+CREATE TABLE posts (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    title VARCHAR(200) NOT NULL,
+    url VARCHAR(150) UNIQUE NOT NULL,
+    description TEXT,
+    published_at TIMESTAMP NOT NULL,
+    feed_id UUID NOT NULL REFERENCES feeds(id) ON DELETE CASCADE,
+    guid TEXT,
+    content TEXT,
+    content_hash VARCHAR(64) NOT NULL DEFAULT '',
+    revised_at TIMESTAMP,
+    search_vector TEXT NOT NULL DEFAULT ''
+);
+
+CREATE UNIQUE INDEX posts_feed_id_guid_idx ON posts (feed_id, guid);
+CREATE INDEX posts_feed_id_published_at_id_idx ON posts (feed_id, published_at, id);
+CREATE INDEX posts_feed_id_created_at_id_idx ON posts (feed_id, created_at, id);
+
+CREATE TABLE post_revisions (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    title VARCHAR(200) NOT NULL,
+    description TEXT,
+    content TEXT,
+    content_hash VARCHAR(64) NOT NULL
+);
+
+CREATE INDEX post_revisions_post_id_idx ON post_revisions (post_id, created_at);
+
+CREATE TABLE hooks (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    feed_id UUID REFERENCES feeds(id) ON DELETE CASCADE,
+    kind VARCHAR(20) NOT NULL,
+    target TEXT NOT NULL,
+    template VARCHAR(20) NOT NULL,
+    max_attempts INTEGER NOT NULL
+);
+
+CREATE TABLE hook_deliveries (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    hook_id UUID NOT NULL REFERENCES hooks(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    attempts INTEGER NOT NULL,
+    succeeded BOOLEAN NOT NULL,
+    error TEXT
+);
+
+CREATE INDEX hook_deliveries_hook_id_idx ON hook_deliveries (hook_id, created_at);
+
+CREATE TABLE post_reads (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    read_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX post_reads_post_id_idx ON post_reads (post_id);
+
+CREATE TABLE post_stars (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    starred_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX post_stars_post_id_idx ON post_stars (post_id);
+
+CREATE TABLE read_later (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    post_id UUID NOT NULL REFERENCES posts(id) ON DELETE CASCADE,
+    position INTEGER NOT NULL,
+    added_at TIMESTAMP NOT NULL,
+    PRIMARY KEY (user_id, post_id)
+);
+
+CREATE INDEX read_later_user_id_position_idx ON read_later (user_id, position);
+CREATE INDEX read_later_post_id_idx ON read_later (post_id);
+
+CREATE TABLE folders (
+    id UUID PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP NOT NULL,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(50) NOT NULL,
+    UNIQUE (user_id, name)
+);
+
+CREATE TABLE feed_follow_folders (
+    feed_follow_id UUID NOT NULL REFERENCES feed_follows(id) ON DELETE CASCADE,
+    folder_id UUID NOT NULL REFERENCES folders(id) ON DELETE CASCADE,
+    PRIMARY KEY (feed_follow_id, folder_id)
+);
+
+CREATE INDEX feed_follow_folders_folder_id_idx ON feed_follow_folders (folder_id);
+
+-- +goose Down
+DROP TABLE feed_follow_folders;
+DROP TABLE folders;
+DROP TABLE read_later;
+DROP TABLE post_stars;
+DROP TABLE post_reads;
+DROP TABLE hook_deliveries;
+DROP TABLE hooks;
+DROP TABLE post_revisions;
+DROP TABLE posts;
+DROP TABLE feed_follows;
+DROP TABLE feeds;
+DROP TABLE users;
@@ -0,0 +1,6 @@
+-- +goose Up
+-- sqlite keeps times as text, they are written as UTC already
+ALTER TABLE users ADD COLUMN timezone TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN timezone;
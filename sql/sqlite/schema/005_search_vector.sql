@@ -0,0 +1,6 @@
+-- +goose Up
+-- Postgres reads posts through a view leaving search_vector out, posts keeps the same columns in both databases
+ALTER TABLE posts DROP COLUMN search_vector;
+
+-- +goose Down
+ALTER TABLE posts ADD COLUMN search_vector TEXT NOT NULL DEFAULT '';